@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/AndreeJait/ntaps/gen/spec"
+	"github.com/AndreeJait/ntaps/internal/paths"
+)
+
+func runApplyCmd(args []string) {
+	fs := flag.NewFlagSet("apply", flag.ExitOnError)
+
+	var file string
+	fs.StringVar(&file, "f", "", "spec file (YAML or JSON), e.g. service.yaml")
+	fs.StringVar(&file, "file", "", "alias of -f")
+	_ = fs.Parse(args)
+
+	if file == "" {
+		exitErr("usage: ntaps apply -f <service.yaml>")
+	}
+
+	s, err := spec.Load(paths.UserPath(file))
+	if err != nil {
+		exitErr(err.Error())
+	}
+	if err := spec.Apply(s); err != nil {
+		exitErr(err.Error())
+	}
+
+	fmt.Printf("✅ Done: applied %s (%d modules, %d repositories, %d outbounds)\n",
+		file, len(s.Modules), len(s.Repositories), len(s.Outbounds))
+}
@@ -4,6 +4,9 @@ import (
 	"fmt"
 	"os"
 	"strings"
+
+	"github.com/AndreeJait/ntaps/gen/usecase"
+	"github.com/AndreeJait/ntaps/internal/util"
 )
 
 func exitErr(msg string) {
@@ -17,3 +20,95 @@ func isPascalCase(s string) bool {
 	}
 	return strings.ToUpper(s[:1]) == s[:1]
 }
+
+// fieldsFlag collects a repeatable --field name:type[:rules].
+type fieldsFlag []util.Field
+
+func (f *fieldsFlag) String() string {
+	var parts []string
+	for _, fld := range *f {
+		parts = append(parts, fld.Name+":"+fld.Type)
+	}
+	return strings.Join(parts, ",")
+}
+
+func (f *fieldsFlag) Set(v string) error {
+	fld, err := util.ParseField(v)
+	if err != nil {
+		return err
+	}
+	*f = append(*f, fld)
+	return nil
+}
+
+// paramsFlag collects a repeatable --query/--header/--cookie
+// name:type[:rules],... list.
+type paramsFlag []util.Field
+
+func (f *paramsFlag) String() string {
+	return (*fieldsFlag)(f).String()
+}
+
+func (f *paramsFlag) Set(v string) error {
+	fields, err := util.ParseParams(v)
+	if err != nil {
+		return err
+	}
+	*f = append(*f, fields...)
+	return nil
+}
+
+// validateFlag collects a repeatable --validate name:rules.
+type validateFlag [][2]string
+
+func (v *validateFlag) String() string {
+	var parts []string
+	for _, r := range *v {
+		parts = append(parts, r[0]+":"+r[1])
+	}
+	return strings.Join(parts, " ")
+}
+
+func (v *validateFlag) Set(s string) error {
+	name, rules, ok := strings.Cut(s, ":")
+	name, rules = strings.TrimSpace(name), strings.TrimSpace(rules)
+	if !ok || name == "" || rules == "" {
+		return fmt.Errorf("--validate wants name:rules (e.g. email:required,email), got %q", s)
+	}
+	if err := usecase.CheckRules(rules); err != nil {
+		return err
+	}
+	*v = append(*v, [2]string{name, rules})
+	return nil
+}
+
+// applyValidation tags the fields of the usecase DTO typeName with the
+// --validate rules.
+func applyValidation(pkg, typeName string, rules validateFlag) error {
+	for _, r := range rules {
+		if err := usecase.SetValidation(pkg, typeName, r[0], r[1]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// pageFlag is --paginated (offset) or --paginated=offset|cursor.
+type pageFlag string
+
+func (p *pageFlag) String() string   { return string(*p) }
+func (p *pageFlag) IsBoolFlag() bool { return true }
+
+func (p *pageFlag) Set(v string) error {
+	switch v {
+	case "true", "offset":
+		*p = "offset"
+	case "cursor":
+		*p = "cursor"
+	case "false":
+		*p = ""
+	default:
+		return fmt.Errorf("--paginated wants offset or cursor, got %q", v)
+	}
+	return nil
+}
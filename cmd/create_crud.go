@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/AndreeJait/ntaps/gen/crud"
+	"github.com/AndreeJait/ntaps/internal/util"
+)
+
+func runCreateCrudCmd(args []string) {
+	fs := flag.NewFlagSet("create-crud", flag.ExitOnError)
+
+	var resource, fieldSpec, endpointType, tag string
+	fs.StringVar(&resource, "resource", "", "resource / package name (e.g., customer)")
+	fs.StringVar(&fieldSpec, "fields", "", `resource fields as name:type[:rules] (e.g., "name:string:required,email:string,status:int")`)
+	fs.StringVar(&endpointType, "endpointType", "public", "public|internal|private")
+	fs.StringVar(&tag, "tag", "", "swagger tag; default: CamelCase of --resource")
+	_ = fs.Parse(args)
+
+	if resource == "" {
+		exitErr(`usage: ntaps create-crud --resource=<name> --fields="name:string,..." [--endpointType=public|internal|private] [--tag=<Tag>]`)
+	}
+	fields, err := util.ParseFields(fieldSpec)
+	if err != nil {
+		exitErr("--fields: " + err.Error())
+	}
+	switch strings.ToLower(endpointType) {
+	case "public", "internal", "private":
+	default:
+		exitErr("--endpointType must be one of public|internal|private")
+	}
+
+	if err := crud.Run(resource, fields, endpointType, tag); err != nil {
+		exitErr(err.Error())
+	}
+
+	fmt.Printf("✅ Done: CRUD resource=%s (%d fields, %s)\n", resource, len(fields), endpointType)
+}
@@ -7,6 +7,9 @@ import (
 	"strings"
 
 	"github.com/AndreeJait/ntaps/gen/handler"
+	"github.com/AndreeJait/ntaps/gen/repo"
+	"github.com/AndreeJait/ntaps/gen/usecase"
+	"github.com/AndreeJait/ntaps/internal/tmpl"
 	"github.com/AndreeJait/ntaps/internal/util"
 )
 
@@ -15,6 +18,13 @@ func runCreateHandlerCmd(args []string) {
 
 	var pkg, ucPkg, endpointType, endpoint, ucMethodName, method, tag, verb string
 	var withParamUc, withResponseUc bool
+	var status int
+	var uploadSpec, download string
+	var paginated pageFlag
+	var withRepo bool
+	var fields, respFields fieldsFlag
+	var query, header, cookie paramsFlag
+	var validate validateFlag
 
 	fs.StringVar(&pkg, "pkg", "", "handler package name (e.g., send)")
 	fs.StringVar(&ucPkg, "ucPkg", "", "usecase package to call (e.g., send)")
@@ -25,11 +35,22 @@ func runCreateHandlerCmd(args []string) {
 	fs.StringVar(&ucMethodName, "ucMethodName", "", "usecase method name (PascalCase)")
 	fs.StringVar(&method, "method", "", "handler method name (lowerCamel, e.g., submitCashToCash)")
 	fs.StringVar(&tag, "tag", "", "swagger tag; default: CamelCase of --pkg")
-	fs.StringVar(&verb, "verb", "POST", "HTTP verb: GET|POST|PUT|DELETE")
+	fs.StringVar(&verb, "verb", "POST", "HTTP verb: GET|POST|PUT|PATCH|DELETE|HEAD|OPTIONS")
+	fs.IntVar(&status, "status", 0, "success status; default 201 for POST, 204 for DELETE without Response, else 200")
+	fs.Var(&fields, "field", "Request field name:type[:rules], repeatable; tagged param (path params), query (GET/DELETE) or json")
+	fs.Var(&respFields, "respField", "Response field name:type[:rules], repeatable")
+	fs.Var(&query, "query", "Request query params name:type[:rules],... (e.g., page:int,limit:int)")
+	fs.Var(&header, "header", "Request headers name:type[:rules],... (e.g., X-Request-ID:string)")
+	fs.StringVar(&uploadSpec, "upload", "", "multipart file form field [,maxSize=10MB,types=image/png|image/jpeg]; read into the Request")
+	fs.Var(&paginated, "paginated", "offset (page/limit, the default) or cursor pagination: query params, page envelope Response, <UcMethod>Item")
+	fs.BoolVar(&withRepo, "withRepo", false, "with --paginated: also a postgres repository method <UcMethod> in package --ucPkg taking limit/offset or a keyset")
+	fs.StringVar(&download, "download", "", "csv|file: stream the Response as a download instead of JSON")
+	fs.Var(&cookie, "cookie", "Request cookies name:string[:rules],... (e.g., session:string)")
+	fs.Var(&validate, "validate", "Request field rules name:rules, repeatable (required, min, max, email, oneof); checked by the handler")
 	_ = fs.Parse(args)
 
 	if len(args) == 0 || os.Getenv("NTAPS_INTERACTIVE") == "1" {
-		interactiveHandler(&pkg, &ucPkg, &withParamUc, &withResponseUc, &ucMethodName, &method, &endpointType, &endpoint, &tag, &verb)
+		interactiveHandler(&pkg, &ucPkg, &withParamUc, &withResponseUc, &ucMethodName, &method, &endpointType, &endpoint, &tag, &verb, &status, &fields, &respFields, &query, &header, &cookie, &uploadSpec, &download)
 	}
 
 	// Skeleton mode: just create pkg & register
@@ -55,10 +76,100 @@ func runCreateHandlerCmd(args []string) {
 		endpoint = "/" + endpoint
 	}
 	verb = strings.ToUpper(strings.TrimSpace(verb))
-	switch verb {
-	case "GET", "POST", "PUT", "DELETE":
-	default:
-		exitErr("--verb must be one of GET|POST|PUT|DELETE")
+	if !handler.IsVerb(verb) {
+		exitErr("--verb must be one of " + strings.Join(handler.Verbs, "|"))
+	}
+	if download != "" {
+		if download != "csv" && download != "file" {
+			exitErr("--download must be one of " + strings.Join(handler.Downloads, "|"))
+		}
+		if !withResponseUc {
+			exitErr("--download needs --withResponseUc: the usecase hands the content over in its Response")
+		}
+		if status == 0 {
+			status = 200
+		}
+	}
+	if status == 0 {
+		status = handler.DefaultStatus(verb, withResponseUc)
+	}
+	if err := handler.CheckStatus(status, withResponseUc); err != nil {
+		exitErr("--status: " + err.Error())
+	}
+	params := map[string][]util.Field{"query": query, "header": header, "cookie": cookie}
+	hasParams := len(query)+len(header)+len(cookie) > 0
+	if (len(fields) > 0 || len(validate) > 0 || hasParams) && !withParamUc || len(respFields) > 0 && !withResponseUc {
+		exitErr("--field/--query/--header/--cookie/--validate need --withParamUc and --respField needs --withResponseUc")
+	}
+	if paginated != "" && (!withParamUc || !withResponseUc) {
+		exitErr("--paginated needs --withParamUc and --withResponseUc")
+	}
+	if withRepo && paginated == "" {
+		exitErr("--withRepo needs --paginated")
+	}
+	if err := handler.CheckCookieFields(cookie); err != nil {
+		exitErr(err.Error())
+	}
+	var upload *tmpl.HandlerUpload
+	if uploadSpec != "" {
+		var err error
+		if upload, err = handler.ParseUpload(uploadSpec); err != nil {
+			exitErr("--upload: " + err.Error())
+		}
+		if !withParamUc {
+			exitErr("--upload needs --withParamUc: the file reaches the usecase through the Request")
+		}
+		if verb != "POST" && verb != "PUT" && verb != "PATCH" {
+			exitErr("--upload needs --verb POST, PUT or PATCH")
+		}
+	}
+
+	// typed fields go in before the route so the path-param enrichment
+	// sees them instead of adding string duplicates
+	if len(fields) > 0 || len(respFields) > 0 || len(validate) > 0 || hasParams || upload != nil || download != "" || paginated != "" {
+		if err := usecase.Run(ucPkg, ucMethodName, withParamUc, withResponseUc); err != nil {
+			exitErr(err.Error())
+		}
+	}
+	if len(fields) > 0 {
+		if err := usecase.AddFields(ucPkg, ucMethodName+"Request", handler.RequestFields(fields, verb, endpoint, upload != nil)); err != nil {
+			exitErr(err.Error())
+		}
+	}
+	if paginated != "" {
+		if err := usecase.Paginate(ucPkg, ucMethodName, string(paginated)); err != nil {
+			exitErr(err.Error())
+		}
+	}
+	if len(respFields) > 0 {
+		// paginated: the fields describe one item
+		target := ucMethodName + "Response"
+		if paginated != "" {
+			target = usecase.PageItem(ucMethodName)
+		}
+		if err := usecase.AddFields(ucPkg, target, util.TagFields(respFields, "json")); err != nil {
+			exitErr(err.Error())
+		}
+	}
+	if download != "" {
+		if err := usecase.AddFields(ucPkg, ucMethodName+"Response", handler.DownloadFields(download)); err != nil {
+			exitErr(err.Error())
+		}
+	}
+	if upload != nil {
+		if err := usecase.AddFields(ucPkg, ucMethodName+"Request", handler.UploadFields(upload)); err != nil {
+			exitErr(err.Error())
+		}
+	}
+	for _, in := range handler.ParamSources {
+		if len(params[in]) > 0 {
+			if err := usecase.AddFields(ucPkg, ucMethodName+"Request", util.TagFields(params[in], in)); err != nil {
+				exitErr(err.Error())
+			}
+		}
+	}
+	if err := applyValidation(ucPkg, ucMethodName+"Request", validate); err != nil {
+		exitErr(err.Error())
 	}
 
 	if err := handler.Run(
@@ -72,9 +183,18 @@ func runCreateHandlerCmd(args []string) {
 		method,
 		tag,
 		verb,
+		status,
+		upload,
+		download,
 	); err != nil {
 		exitErr(err.Error())
 	}
 
-	fmt.Printf("✅ Done: handler=%s method=%s (%s %s) → uc=%s.%s\n", pkg, method, verb, endpointType, ucPkg, ucMethodName)
+	if withRepo {
+		if err := repo.Paginate(ucPkg, ucMethodName, string(paginated), ucPkg); err != nil {
+			exitErr(err.Error())
+		}
+	}
+
+	fmt.Printf("✅ Done: handler=%s method=%s (%s %s, %d) → uc=%s.%s\n", pkg, method, verb, endpointType, status, ucPkg, ucMethodName)
 }
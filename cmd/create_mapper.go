@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/AndreeJait/ntaps/gen/mapper"
+)
+
+func runCreateMapperCmd(args []string) {
+	fs := flag.NewFlagSet("create-mapper", flag.ExitOnError)
+
+	var fromSpec, toSpec, inMethod string
+	fs.StringVar(&fromSpec, "from", "", "source type <layer>/<pkg>.<Type>, layer usecase|repo|outbound (e.g., usecase/send.SubmitRequest)")
+	fs.StringVar(&toSpec, "to", "", "target type, same form (e.g., repo/user.UpdateUserStatusParam)")
+	fs.StringVar(&inMethod, "inMethod", "", "also call the mapper in this usecase method (its request must be --from)")
+	_ = fs.Parse(args)
+
+	if fromSpec == "" || toSpec == "" {
+		exitErr("usage: ntaps create-mapper --from=<layer>/<pkg>.<Type> --to=<layer>/<pkg>.<Type> [--inMethod=<UsecaseMethod>]")
+	}
+	from, err := mapper.ParseRef(fromSpec)
+	if err != nil {
+		exitErr(err.Error())
+	}
+	to, err := mapper.ParseRef(toSpec)
+	if err != nil {
+		exitErr(err.Error())
+	}
+
+	res, err := mapper.Run(from, to, inMethod)
+	if err != nil {
+		exitErr(err.Error())
+	}
+	for _, t := range res.TODOs {
+		fmt.Println("ℹ️  unmapped", t)
+	}
+	fmt.Printf("✅ Done: %s in %s maps %s → %s (%d mapped, %d TODO)\n", res.Func, res.Path, from, to, len(res.Mapped), len(res.TODOs))
+}
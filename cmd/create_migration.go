@@ -0,0 +1,32 @@
+package cmd
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/AndreeJait/ntaps/gen/migration"
+	"github.com/AndreeJait/ntaps/internal/paths"
+)
+
+func runCreateMigrationCmd(args []string) {
+	fs := flag.NewFlagSet("create-migration", flag.ExitOnError)
+
+	var name, format string
+	fs.StringVar(&name, "name", "", "migration name, snake_case (e.g., add_status_to_users)")
+	fs.StringVar(&format, "format", paths.MigrationFormat, "migration layout: "+strings.Join(migration.Formats, "|"))
+	_ = fs.Parse(args)
+
+	if name == "" {
+		exitErr("usage: ntaps create-migration --name=<snake_case> [--format=" + strings.Join(migration.Formats, "|") + "]")
+	}
+
+	files, err := migration.Create(name, format, "", "")
+	if err != nil {
+		exitErr(err.Error())
+	}
+	for _, f := range files {
+		fmt.Println("•", f)
+	}
+	fmt.Printf("✅ Done: migration %s (%s) in %s\n", name, format, paths.MigrationsDir)
+}
@@ -6,6 +6,7 @@ import (
 	"os"
 
 	"github.com/AndreeJait/ntaps/gen/outbound"
+	"github.com/AndreeJait/ntaps/internal/util"
 )
 
 func runCreateOutboundCmd(args []string) {
@@ -13,15 +14,18 @@ func runCreateOutboundCmd(args []string) {
 
 	var pkg, method string
 	var withParam, withResp bool
+	var fields, respFields fieldsFlag
 
 	fs.StringVar(&pkg, "pkg", "", "outbound package name (e.g., email)")
 	fs.StringVar(&method, "method", "", "method name in PascalCase (e.g., SendEmailActivation)")
 	fs.BoolVar(&withParam, "withParam", false, "generate <Method>Request")
 	fs.BoolVar(&withResp, "withResp", false, "generate <Method>Response")
+	fs.Var(&fields, "field", "Request field name:type[:rules], repeatable (e.g., to:string:required,email)")
+	fs.Var(&respFields, "respField", "Response field name:type[:rules], repeatable")
 	_ = fs.Parse(args)
 
 	if len(args) == 0 || os.Getenv("NTAPS_INTERACTIVE") == "1" {
-		interactiveOutbound(&pkg, &method, &withParam, &withResp)
+		interactiveOutbound(&pkg, &method, &withParam, &withResp, &fields, &respFields)
 	}
 
 	if pkg == "" {
@@ -30,10 +34,26 @@ func runCreateOutboundCmd(args []string) {
 	if method != "" && !isPascalCase(method) {
 		exitErr("--method must be PascalCase")
 	}
+	if method == "" && len(fields)+len(respFields) > 0 {
+		exitErr("--field/--respField need --method")
+	}
+	if len(fields) > 0 && !withParam || len(respFields) > 0 && !withResp {
+		exitErr("--field needs --withParam and --respField needs --withResp")
+	}
 
 	if err := outbound.Run(pkg, method, withParam, withResp); err != nil {
 		exitErr(err.Error())
 	}
+	if len(fields) > 0 {
+		if err := outbound.AddFields(pkg, method+"Request", util.TagFields(fields, "json")); err != nil {
+			exitErr(err.Error())
+		}
+	}
+	if len(respFields) > 0 {
+		if err := outbound.AddFields(pkg, method+"Response", util.TagFields(respFields, "json")); err != nil {
+			exitErr(err.Error())
+		}
+	}
 
 	if method == "" {
 		fmt.Printf("✅ Done: outbound=%s created\n", pkg)
@@ -4,15 +4,21 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"slices"
+	"strings"
 
+	"github.com/AndreeJait/ntaps/gen/migration"
 	"github.com/AndreeJait/ntaps/gen/repo"
+	"github.com/AndreeJait/ntaps/internal/paths"
+	"github.com/AndreeJait/ntaps/internal/util"
 )
 
 func runCreateRepositoryCmd(args []string) {
 	fs := flag.NewFlagSet("create-repository", flag.ExitOnError)
 
-	var rtype, pkg, method, addToUC string
-	var withParam, withResp, withTx bool
+	var rtype, pkg, method, addToUC, fromQuery, fromTable, nullable string
+	var withParam, withResp, withTx, fromSqlc, sqlStub, many, withTable bool
+	var fields, respFields fieldsFlag
 
 	fs.StringVar(&rtype, "type", "postgres", "repository backend type (postgres)")
 	fs.StringVar(&pkg, "pkg", "", "repository package (e.g., user)")
@@ -21,25 +27,101 @@ func runCreateRepositoryCmd(args []string) {
 	fs.BoolVar(&withResp, "withResponseRepo", false, "generate <Method>Response")
 	fs.BoolVar(&withTx, "withTx", false, "include tx pgx.Tx parameter")
 	fs.StringVar(&addToUC, "addToUC", "", "usecase pkg to wire this repo into (e.g., send)")
+	fs.StringVar(&fromQuery, "fromQuery", "", "generate the method, DTOs and body from this sqlc query (e.g., GetUserByID)")
+	fs.StringVar(&fromTable, "fromTable", "", "derive <Method>Param/Response from this table's CREATE/ALTER TABLE migrations (e.g., users)")
+	fs.StringVar(&nullable, "nullable", "pgtype", "with --fromTable: nullable columns as pgtype|pointer")
+	fs.BoolVar(&fromSqlc, "fromSqlc", false, "like --fromQuery, for every sqlc query")
+	fs.BoolVar(&sqlStub, "sqlStub", false, "append a -- name: stub to the package's query file and list it in sqlc.yaml")
+	fs.BoolVar(&withTable, "withTable", false, "for a new package: also create a create_<pkg>s_table migration (format: migrationFormat in .ntaps.yaml)")
+	fs.BoolVar(&many, "many", false, "with --sqlStub: the query returns rows (:many); needs --withResponseRepo")
+	fs.Var(&fields, "field", "Param field name:type[:rules], repeatable (e.g., status:int)")
+	fs.Var(&respFields, "respField", "Response field name:type[:rules], repeatable")
 	_ = fs.Parse(args)
 
 	if len(args) == 0 || os.Getenv("NTAPS_INTERACTIVE") == "1" {
-		interactiveRepository(&rtype, &pkg, &method, &withParam, &withResp, &withTx, &addToUC)
+		interactiveRepository(&rtype, &pkg, &method, &withParam, &withResp, &withTx, &addToUC, &fields, &respFields)
 	}
 
 	if rtype != "postgres" {
 		exitErr("--type currently supports only 'postgres'")
 	}
+
+	if fromQuery != "" || fromSqlc {
+		if pkg == "" {
+			exitErr("usage: ntaps create-repository --pkg=<pkg> --fromQuery=<SqlcQuery>|--fromSqlc [--withTx] [--addToUC=<usecase>]")
+		}
+		if fromSqlc {
+			names, err := repo.RunFromSqlc(pkg, withTx, addToUC)
+			if err != nil {
+				exitErr(err.Error())
+			}
+			fmt.Printf("✅ Done: repository=%s generated from %d sqlc queries (tx=%v) wiredToUC=%s\n", pkg, len(names), withTx, addToUC)
+			return
+		}
+		if err := repo.RunFromQuery(pkg, fromQuery, withTx, addToUC); err != nil {
+			exitErr(err.Error())
+		}
+		fmt.Printf("✅ Done: repository=%s method=%s from sqlc (tx=%v) wiredToUC=%s\n", pkg, fromQuery, withTx, addToUC)
+		return
+	}
 	if pkg == "" || method == "" {
 		exitErr("usage: ntaps create-repository --type=postgres --pkg=<pkg> --method=<Pascal> [--withParamRepo] [--withResponseRepo] [--withTx] [--addToUC=<usecase>]")
 	}
 	if !isPascalCase(method) {
 		exitErr("--method must be PascalCase")
 	}
+	if many && !withResp {
+		exitErr("--many needs --withResponseRepo")
+	}
+	if len(fields) > 0 && !withParam || len(respFields) > 0 && !withResp {
+		exitErr("--field needs --withParamRepo and --respField needs --withResponseRepo")
+	}
 
-	if err := repo.Run(pkg, method, withParam, withResp, withTx, addToUC); err != nil {
+	// the table migration goes first so --fromTable can read it
+	isNew := !repo.Exists(pkg)
+	if withTable {
+		if !isNew {
+			fmt.Printf("ℹ️  repository %s already existed, no table migration created\n", pkg)
+		} else {
+			files, err := migration.CreateTable(migration.TableName(pkg), paths.MigrationFormat)
+			if err != nil {
+				exitErr(err.Error())
+			}
+			for _, f := range files {
+				fmt.Println("• migration", f)
+			}
+		}
+	}
+	if fromTable != "" {
+		if !slices.Contains(repo.NullableStyles, nullable) {
+			exitErr("--nullable must be one of " + strings.Join(repo.NullableStyles, "|"))
+		}
+		if !withParam && !withResp {
+			withParam, withResp = true, true
+		}
+		if err := repo.RunFromTable(pkg, method, fromTable, nullable, withParam, withResp, withTx, addToUC); err != nil {
+			exitErr(err.Error())
+		}
+	} else if err := repo.Run(pkg, method, withParam, withResp, withTx, addToUC); err != nil {
 		exitErr(err.Error())
 	}
+	if len(fields) > 0 {
+		if err := repo.AddFields(pkg, method+"Param", util.TagFields(fields, "db")); err != nil {
+			exitErr(err.Error())
+		}
+	}
+	if len(respFields) > 0 {
+		if err := repo.AddFields(pkg, method+"Response", util.TagFields(respFields, "db")); err != nil {
+			exitErr(err.Error())
+		}
+	}
+	if sqlStub {
+		file, err := repo.EnsureQueryStub(pkg, method, repo.QueryKind(withResp, many))
+		if err != nil {
+			exitErr(err.Error())
+		}
+		fmt.Printf("• sqlc query %s :%s in %s\n", method, repo.QueryKind(withResp, many), file)
+	}
 
 	fmt.Printf(
 		"✅ Done: repository=%s method=%s (param=%v, resp=%v, tx=%v) wiredToUC=%s\n",
@@ -5,7 +5,9 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/AndreeJait/ntaps/gen/repo"
 	"github.com/AndreeJait/ntaps/gen/usecase"
+	"github.com/AndreeJait/ntaps/internal/util"
 )
 
 func runCreateUsecaseCmd(args []string) {
@@ -13,15 +15,24 @@ func runCreateUsecaseCmd(args []string) {
 
 	var pkg, method string
 	var withParam, withResp bool
+	var fields, respFields fieldsFlag
+	var validate validateFlag
+	var paginated pageFlag
+	var withRepo bool
 
 	fs.StringVar(&pkg, "pkg", "", "usecase package name (e.g., send)")
 	fs.StringVar(&method, "method", "", "method name in PascalCase (e.g., SubmitCashToCash)")
 	fs.BoolVar(&withParam, "withParam", false, "generate a Param struct <MethodName>Request")
 	fs.BoolVar(&withResp, "withResponse", false, "generate a Response struct <MethodName>Response")
+	fs.Var(&fields, "field", "Request field name:type[:rules], repeatable (e.g., email:string:required,email)")
+	fs.Var(&respFields, "respField", "Response field name:type[:rules], repeatable")
+	fs.Var(&paginated, "paginated", "offset (page/limit, the default) or cursor pagination: query params, page envelope Response, <Method>Item")
+	fs.BoolVar(&withRepo, "withRepo", false, "with --paginated: also a postgres repository method <Method> in package --pkg taking limit/offset or a keyset")
+	fs.Var(&validate, "validate", "Request field rules name:rules, repeatable (required, min, max, email, oneof)")
 	_ = fs.Parse(args)
 
 	if len(args) == 0 || os.Getenv("NTAPS_INTERACTIVE") == "1" {
-		interactiveUsecase(&pkg, &method, &withParam, &withResp)
+		interactiveUsecase(&pkg, &method, &withParam, &withResp, &fields, &respFields)
 	}
 
 	if pkg == "" || method == "" {
@@ -30,10 +41,50 @@ func runCreateUsecaseCmd(args []string) {
 	if !isPascalCase(method) {
 		exitErr("method must be PascalCase")
 	}
+	if (len(fields) > 0 || len(validate) > 0) && !withParam || len(respFields) > 0 && !withResp {
+		exitErr("--field/--validate need --withParam and --respField needs --withResponse")
+	}
+	if paginated != "" && (!withParam || !withResp) {
+		exitErr("--paginated needs --withParam and --withResponse")
+	}
+	if withRepo && paginated == "" {
+		exitErr("--withRepo needs --paginated")
+	}
 
 	if err := usecase.Run(pkg, method, withParam, withResp); err != nil {
 		exitErr(err.Error())
 	}
+	if len(fields) > 0 {
+		if err := usecase.AddFields(pkg, method+"Request", util.TagFields(fields, "json")); err != nil {
+			exitErr(err.Error())
+		}
+	}
+	if paginated != "" {
+		if err := usecase.Paginate(pkg, method, string(paginated)); err != nil {
+			exitErr(err.Error())
+		}
+	}
+	if len(respFields) > 0 {
+		// paginated: the fields describe one item
+		target := method + "Response"
+		if paginated != "" {
+			target = usecase.PageItem(method)
+		}
+		if err := usecase.AddFields(pkg, target, util.TagFields(respFields, "json")); err != nil {
+			exitErr(err.Error())
+		}
+	}
+	if err := applyValidation(pkg, method+"Request", validate); err != nil {
+		exitErr(err.Error())
+	}
+	if err := usecase.SyncValidators(pkg); err != nil {
+		exitErr(err.Error())
+	}
+	if withRepo {
+		if err := repo.Paginate(pkg, method, string(paginated), pkg); err != nil {
+			exitErr(err.Error())
+		}
+	}
 
 	fmt.Printf("✅ Done: usecase=%s method=%s (withParam=%v, withResponse=%v)\n", pkg, method, withParam, withResp)
 }
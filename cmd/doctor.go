@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/AndreeJait/ntaps/internal/doctor"
+)
+
+func runDoctorCmd(args []string) {
+	fs := flag.NewFlagSet("doctor", flag.ExitOnError)
+
+	var strict bool
+	fs.BoolVar(&strict, "strict", false, "exit non-zero on warnings too")
+	_ = fs.Parse(args)
+
+	problems := doctor.Run()
+	errs, warns := 0, 0
+	for _, p := range problems {
+		fmt.Println(p)
+		if p.Severity == doctor.Error {
+			errs++
+		} else {
+			warns++
+		}
+	}
+
+	if errs > 0 || (strict && warns > 0) {
+		fmt.Fprintf(os.Stderr, "❌ doctor: %d error(s), %d warning(s)\n", errs, warns)
+		os.Exit(1)
+	}
+	if warns > 0 {
+		fmt.Printf("✅ Done: no errors, %d warning(s)\n", warns)
+		return
+	}
+	fmt.Println("✅ Done: every anchor ntaps relies on is in place")
+}
@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"flag"
+	"fmt"
+	"path/filepath"
+
+	"github.com/AndreeJait/ntaps/internal/paths"
+	"github.com/AndreeJait/ntaps/internal/tmpl"
+	"github.com/AndreeJait/ntaps/internal/util"
+)
+
+func runExportTemplatesCmd(args []string) {
+	fs := flag.NewFlagSet("export-templates", flag.ExitOnError)
+
+	var out string
+	var force bool
+	fs.StringVar(&out, "out", "", "target directory; default: the project template dir ("+paths.TemplatesDir+")")
+	fs.BoolVar(&force, "force", false, "overwrite templates that already exist")
+	_ = fs.Parse(args)
+
+	if out == "" {
+		out = paths.TemplatesDir
+	} else {
+		out = paths.UserPath(out)
+	}
+
+	names := fs.Args()
+	if len(names) == 0 {
+		names = tmpl.Names()
+	}
+
+	for _, name := range names {
+		src, err := tmpl.Default(name)
+		if err != nil {
+			exitErr(fmt.Sprintf("unknown template %q (available: %v)", name, tmpl.Names()))
+		}
+		path := filepath.Join(out, name)
+		if _, err := util.Stat(path); err == nil && !force {
+			fmt.Printf("ℹ️  %s exists, skipping (use --force to overwrite)\n", path)
+			continue
+		}
+		if err := util.WriteFile(path, string(src)); err != nil {
+			exitErr(err.Error())
+		}
+		fmt.Println("•", path)
+	}
+
+	fmt.Printf("✅ Done: templates exported to %s — edit them to customize generated code\n", out)
+}
@@ -0,0 +1,32 @@
+package cmd
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/AndreeJait/ntaps/gen/openapi"
+	"github.com/AndreeJait/ntaps/internal/paths"
+)
+
+func runFromOpenAPICmd(args []string) {
+	fs := flag.NewFlagSet("from-openapi", flag.ExitOnError)
+
+	var file string
+	fs.StringVar(&file, "spec", "", "OpenAPI 3 document (YAML or JSON), e.g. api.yaml")
+	_ = fs.Parse(args)
+
+	if file == "" {
+		exitErr("usage: ntaps from-openapi --spec=<api.yaml>")
+	}
+
+	doc, err := openapi.Load(paths.UserPath(file))
+	if err != nil {
+		exitErr(err.Error())
+	}
+	n, err := openapi.Generate(doc)
+	if err != nil {
+		exitErr(err.Error())
+	}
+
+	fmt.Printf("✅ Done: generated %d operations from %s\n", n, file)
+}
@@ -0,0 +1,22 @@
+package cmd
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/AndreeJait/ntaps/gen/bootstrap"
+)
+
+func runInitCmd(args []string) {
+	fs := flag.NewFlagSet("init", flag.ExitOnError)
+
+	var module string
+	fs.StringVar(&module, "module", "", "module path for go.mod (required only when go.mod is missing)")
+	_ = fs.Parse(args)
+
+	if err := bootstrap.Run(module); err != nil {
+		exitErr(err.Error())
+	}
+
+	fmt.Println("✅ Done: DI skeleton ready — run `go mod tidy`, then scaffold with create-* commands")
+}
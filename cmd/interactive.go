@@ -4,12 +4,17 @@ import (
 	"bufio"
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
+
+	"github.com/AndreeJait/ntaps/gen/handler"
 )
 
+var stdin = bufio.NewReader(os.Stdin)
+
 // shared reader
 func rd() *bufio.Reader {
-	return bufio.NewReader(os.Stdin)
+	return stdin
 }
 
 func promptString(label, def string) string {
@@ -50,20 +55,55 @@ func promptBool(label string, def bool) bool {
 	}
 }
 
+// promptFields reads name:type[:rules] lines into f until an empty line.
+func promptFields(label string, f *fieldsFlag) {
+	fmt.Printf("%s: one name:type[:rules] per line (e.g. email:string:required,email), empty line to finish\n", label)
+	for {
+		text := promptString("  field", "")
+		if text == "" {
+			return
+		}
+		if err := f.Set(text); err != nil {
+			fmt.Println("  ❌", err)
+		}
+	}
+}
+
+// promptParams reads one name:type,... list into f; empty skips.
+func promptParams(label string, f *paramsFlag) {
+	text := promptString(label+" (name:type,..., empty for none)", "")
+	if text == "" {
+		return
+	}
+	if err := f.Set(text); err != nil {
+		fmt.Println("  ❌", err)
+	}
+}
+
 /* ----- interactive prompts per command ----- */
 
-func interactiveUsecase(pkg, method *string, withParam, withResp *bool) {
+func interactiveUsecase(pkg, method *string, withParam, withResp *bool, fields, respFields *fieldsFlag) {
 	fmt.Println("🛠  create-usecase (press Enter to keep defaults / leave empty)")
 	*pkg = promptString("pkg", *pkg)
 	*method = promptString("method (PascalCase)", *method)
 	*withParam = promptBool("withParam", *withParam)
+	if *withParam {
+		promptFields("Request fields", fields)
+	}
 	*withResp = promptBool("withResponse", *withResp)
+	if *withResp {
+		promptFields("Response fields", respFields)
+	}
 }
 
 func interactiveHandler(
 	pkg, ucPkg *string,
 	withParamUc, withResponseUc *bool,
 	ucMethodName, method, endpointType, endpoint, tag, verb *string,
+	status *int,
+	fields, respFields *fieldsFlag,
+	query, header, cookie *paramsFlag,
+	upload, download *string,
 ) {
 	fmt.Println("🛠  create-handler (press Enter to keep defaults / leave empty)")
 
@@ -86,13 +126,32 @@ func interactiveHandler(
 	if defVerb == "" {
 		defVerb = "POST"
 	}
-	*verb = promptString("verb [GET|POST|PUT|DELETE]", defVerb)
+	*verb = strings.ToUpper(promptString("verb [GET|POST|PUT|PATCH|DELETE|HEAD|OPTIONS]", defVerb))
+	defStatus := *status
+	if defStatus == 0 {
+		defStatus = handler.DefaultStatus(*verb, *withResponseUc)
+	}
+	if n, err := strconv.Atoi(promptString("status", strconv.Itoa(defStatus))); err == nil {
+		*status = n
+	}
+	if *withParamUc {
+		promptFields("Request fields (path params → param, GET/HEAD/DELETE → query, else json)", fields)
+		promptParams("query params", query)
+		promptParams("headers", header)
+		promptParams("cookies", cookie)
+		*upload = promptString("upload (file[,maxSize=10MB,types=image/png|image/jpeg], empty for none)", *upload)
+	}
+	if *withResponseUc {
+		*download = promptString("download [csv|file] (empty for JSON)", *download)
+		promptFields("Response fields", respFields)
+	}
 }
 
 func interactiveRepository(
 	rtype, pkg, method *string,
 	withParam, withResp, withTx *bool,
 	addToUC *string,
+	fields, respFields *fieldsFlag,
 ) {
 	fmt.Println("🛠  create-repository (press Enter to keep defaults / leave empty)")
 
@@ -103,17 +162,29 @@ func interactiveRepository(
 	*pkg = promptString("pkg", *pkg)
 	*method = promptString("method (PascalCase)", *method)
 	*withParam = promptBool("withParamRepo", *withParam)
+	if *withParam {
+		promptFields("Param fields", fields)
+	}
 	*withResp = promptBool("withResponseRepo", *withResp)
+	if *withResp {
+		promptFields("Response fields", respFields)
+	}
 	*withTx = promptBool("withTx", *withTx)
 	*addToUC = promptString("addToUC (optional usecase pkg)", *addToUC)
 }
 
-func interactiveOutbound(pkg, method *string, withParam, withResp *bool) {
+func interactiveOutbound(pkg, method *string, withParam, withResp *bool, fields, respFields *fieldsFlag) {
 	fmt.Println("🛠  create-outbound (press Enter to keep defaults / leave empty)")
 	*pkg = promptString("pkg", *pkg)
 	*method = promptString("method (PascalCase; optional)", *method)
 	*withParam = promptBool("withParam", *withParam)
+	if *withParam {
+		promptFields("Request fields", fields)
+	}
 	*withResp = promptBool("withResp", *withResp)
+	if *withResp {
+		promptFields("Response fields", respFields)
+	}
 }
 
 // NEW: interactive for add-repo-to-usecase
@@ -0,0 +1,32 @@
+package cmd
+
+import (
+	"encoding/json"
+	"flag"
+	"os"
+
+	"github.com/AndreeJait/ntaps/internal/inventory"
+)
+
+func runListCmd(args []string) {
+	fs := flag.NewFlagSet("list", flag.ExitOnError)
+
+	var format string
+	fs.StringVar(&format, "format", "table", "output format: table|json")
+	_ = fs.Parse(args)
+
+	inv := inventory.Collect()
+
+	switch format {
+	case "table":
+		inv.WriteTable(os.Stdout)
+	case "json":
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(inv); err != nil {
+			exitErr(err.Error())
+		}
+	default:
+		exitErr("--format must be table or json")
+	}
+}
@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/AndreeJait/ntaps/gen/handler"
+	"github.com/AndreeJait/ntaps/gen/outbound"
+	"github.com/AndreeJait/ntaps/gen/repo"
+	"github.com/AndreeJait/ntaps/gen/usecase"
+)
+
+// The remove-* commands are flag-only on purpose: deleting code should
+// never be one Enter away in a prompt.
+
+func runRemoveUsecaseMethodCmd(args []string) {
+	fs := flag.NewFlagSet("remove-usecase-method", flag.ExitOnError)
+
+	var pkg, method string
+	var force bool
+	fs.StringVar(&pkg, "pkg", "", "usecase package name (e.g. send)")
+	fs.StringVar(&method, "method", "", "usecase method name in PascalCase")
+	fs.BoolVar(&force, "force", false, "remove even if handlers still call it")
+	_ = fs.Parse(args)
+
+	if pkg == "" || method == "" {
+		exitErr("usage: ntaps remove-usecase-method --pkg=<usecase> --method=<Pascal> [--force]")
+	}
+	if err := usecase.RemoveMethod(pkg, method, force); err != nil {
+		exitErr(err.Error())
+	}
+	fmt.Printf("✅ Removed usecase method %s.%s\n", pkg, method)
+}
+
+func runRemoveHandlerRouteCmd(args []string) {
+	fs := flag.NewFlagSet("remove-handler-route", flag.ExitOnError)
+
+	var pkg, method string
+	var force bool
+	fs.StringVar(&pkg, "pkg", "", "handler package name (e.g. send)")
+	fs.StringVar(&method, "method", "", "handler method name in camelCase (e.g. submitCashToCash)")
+	fs.BoolVar(&force, "force", false, "remove even if the handler method is still referenced")
+	_ = fs.Parse(args)
+
+	if pkg == "" || method == "" {
+		exitErr("usage: ntaps remove-handler-route --pkg=<handler> --method=<camel> [--force]")
+	}
+	if err := handler.RemoveRoute(pkg, method, force); err != nil {
+		exitErr(err.Error())
+	}
+	fmt.Printf("✅ Removed route %s in handler %s\n", method, pkg)
+}
+
+func runRemoveRepositoryCmd(args []string) {
+	fs := flag.NewFlagSet("remove-repository", flag.ExitOnError)
+
+	var pkg string
+	var force bool
+	fs.StringVar(&pkg, "pkg", "", "repository package name (e.g. user)")
+	fs.BoolVar(&force, "force", false, "remove even if usecases still use it")
+	_ = fs.Parse(args)
+
+	if pkg == "" {
+		exitErr("usage: ntaps remove-repository --pkg=<repo> [--force]")
+	}
+	if err := repo.Remove(pkg, force); err != nil {
+		exitErr(err.Error())
+	}
+	fmt.Printf("✅ Removed repository %s and its DI wiring\n", pkg)
+}
+
+func runRemoveOutboundCmd(args []string) {
+	fs := flag.NewFlagSet("remove-outbound", flag.ExitOnError)
+
+	var pkg string
+	var force bool
+	fs.StringVar(&pkg, "pkg", "", "outbound package name (e.g. email)")
+	fs.BoolVar(&force, "force", false, "remove even if it is still imported")
+	_ = fs.Parse(args)
+
+	if pkg == "" {
+		exitErr("usage: ntaps remove-outbound --pkg=<outbound> [--force]")
+	}
+	if err := outbound.Remove(pkg, force); err != nil {
+		exitErr(err.Error())
+	}
+	fmt.Printf("✅ Removed outbound %s\n", pkg)
+}
+
+func runRemoveRepoFromUsecaseCmd(args []string) {
+	fs := flag.NewFlagSet("remove-repo-from-usecase", flag.ExitOnError)
+
+	var repoPkg, ucPkg string
+	var force bool
+	fs.StringVar(&repoPkg, "repoPkg", "", "repository package name (e.g. user)")
+	fs.StringVar(&ucPkg, "ucPkg", "", "usecase package name to remove it from (e.g. send)")
+	fs.BoolVar(&force, "force", false, "remove even if usecase methods still use the repo")
+	_ = fs.Parse(args)
+
+	if repoPkg == "" || ucPkg == "" {
+		exitErr("usage: ntaps remove-repo-from-usecase --repoPkg=<repo> --ucPkg=<usecase> [--force]")
+	}
+	if err := repo.RemoveFromUsecase(repoPkg, ucPkg, force); err != nil {
+		exitErr(err.Error())
+	}
+	fmt.Printf("✅ Unwired repo=%s from usecase=%s\n", repoPkg, ucPkg)
+}
@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/AndreeJait/ntaps/gen/rename"
+)
+
+func runRenameCmd(args []string) {
+	fs := flag.NewFlagSet("rename", flag.ExitOnError)
+
+	var kind, pkg, from, to, layer string
+	fs.StringVar(&kind, "kind", "", "usecase-method | repo-method | outbound-method | package")
+	fs.StringVar(&pkg, "pkg", "", "package owning the method (method kinds only)")
+	fs.StringVar(&from, "from", "", "current name")
+	fs.StringVar(&to, "to", "", "new name")
+	fs.StringVar(&layer, "layer", "", "package kind only: comma-separated "+strings.Join(rename.Layers, ",")+"; default: every layer that has the package")
+	_ = fs.Parse(args)
+
+	if kind == "" || from == "" || to == "" {
+		exitErr("usage: ntaps rename --kind=usecase-method|repo-method|outbound-method|package [--pkg=<pkg>] --from=<old> --to=<new> [--layer=usecase,repository,outbound,handler]")
+	}
+
+	var changed []string
+	var err error
+	switch kind {
+	case "usecase-method", "repo-method", "outbound-method":
+		if pkg == "" {
+			exitErr("--pkg is required for --kind=" + kind)
+		}
+		if !isPascalCase(from) || !isPascalCase(to) {
+			exitErr("--from and --to must be PascalCase")
+		}
+		switch kind {
+		case "usecase-method":
+			changed, err = rename.UsecaseMethod(pkg, from, to)
+		case "repo-method":
+			changed, err = rename.RepoMethod(pkg, from, to)
+		default:
+			changed, err = rename.OutboundMethod(pkg, from, to)
+		}
+	case "package":
+		var layers []string
+		if layer != "" {
+			layers = strings.Split(layer, ",")
+		}
+		changed, err = rename.Package(from, to, layers)
+	default:
+		exitErr("--kind must be usecase-method, repo-method, outbound-method or package")
+	}
+	if err != nil {
+		exitErr(err.Error())
+	}
+
+	for _, p := range changed {
+		fmt.Println("•", p)
+	}
+	fmt.Printf("✅ Renamed %s %s → %s (%d file(s))\n", kind, from, to, len(changed))
+}
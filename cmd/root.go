@@ -3,38 +3,141 @@ package cmd
 import (
 	"fmt"
 	"os"
+
+	"github.com/AndreeJait/ntaps/internal/journal"
+	"github.com/AndreeJait/ntaps/internal/paths"
+	"github.com/AndreeJait/ntaps/internal/util"
 )
 
+// postCommit holds work that must only happen once the staged changes are
+// on disk (journal bookkeeping).
+var postCommit []func() error
+
+func afterCommit(fn func() error) { postCommit = append(postCommit, fn) }
+
 func Execute() {
-	if len(os.Args) < 2 {
+	args, dryRun := extractGlobalFlags(os.Args[1:])
+	if len(args) < 1 {
 		usageAndExit()
 	}
+	util.SetDryRun(dryRun)
+
+	if _, err := paths.Load(); err != nil {
+		exitErr(err.Error())
+	}
 
-	switch os.Args[1] {
+	switch args[0] {
+	case "init":
+		runInitCmd(args[1:])
 	case "create-usecase":
-		runCreateUsecaseCmd(os.Args[2:])
+		runCreateUsecaseCmd(args[1:])
 	case "create-handler":
-		runCreateHandlerCmd(os.Args[2:])
+		runCreateHandlerCmd(args[1:])
 	case "create-repository":
-		runCreateRepositoryCmd(os.Args[2:])
+		runCreateRepositoryCmd(args[1:])
 	case "create-outbound":
-		runCreateOutboundCmd(os.Args[2:])
+		runCreateOutboundCmd(args[1:])
+	case "create-crud":
+		runCreateCrudCmd(args[1:])
+	case "create-migration":
+		runCreateMigrationCmd(args[1:])
+	case "create-mapper":
+		runCreateMapperCmd(args[1:])
 	case "add-repo-to-usecase":
-		runAddRepoToUsecaseCmd(os.Args[2:])
+		runAddRepoToUsecaseCmd(args[1:])
+	case "remove-usecase-method":
+		runRemoveUsecaseMethodCmd(args[1:])
+	case "remove-handler-route":
+		runRemoveHandlerRouteCmd(args[1:])
+	case "remove-repository":
+		runRemoveRepositoryCmd(args[1:])
+	case "remove-outbound":
+		runRemoveOutboundCmd(args[1:])
+	case "remove-repo-from-usecase":
+		runRemoveRepoFromUsecaseCmd(args[1:])
+	case "rename":
+		runRenameCmd(args[1:])
+	case "apply":
+		runApplyCmd(args[1:])
+	case "from-openapi":
+		runFromOpenAPICmd(args[1:])
+	case "undo":
+		runUndoCmd(args[1:])
+	case "export-templates":
+		runExportTemplatesCmd(args[1:])
+	case "doctor":
+		runDoctorCmd(args[1:])
+	case "list":
+		runListCmd(args[1:])
 	default:
 		usageAndExit()
 	}
+
+	if dryRun {
+		util.PrintStagedDiff(os.Stdout)
+		return
+	}
+	if args[0] != "undo" {
+		afterCommit(func() error { return journal.Record(args) })
+	}
+	if err := util.Commit(); err != nil {
+		exitErr(err.Error())
+	}
+	for _, fn := range postCommit {
+		if err := fn(); err != nil {
+			fmt.Fprintln(os.Stderr, "ℹ️  warning: journal not updated:", err)
+		}
+	}
+}
+
+// extractGlobalFlags pulls flags shared by every command (currently only
+// --dry-run) out of args, wherever they appear, so per-command FlagSets and
+// the "no args => interactive" check keep working unchanged.
+func extractGlobalFlags(args []string) (rest []string, dryRun bool) {
+	for _, a := range args {
+		switch a {
+		case "--dry-run", "-dry-run", "--dry-run=true", "-dry-run=true":
+			dryRun = true
+		case "--dry-run=false", "-dry-run=false":
+			dryRun = false
+		default:
+			rest = append(rest, a)
+		}
+	}
+	if os.Getenv("NTAPS_DRY_RUN") == "1" {
+		dryRun = true
+	}
+	return rest, dryRun
 }
 
 func usageAndExit() {
-	fmt.Println(`ntaps <command> [flags]
+	fmt.Println(`ntaps [--dry-run] <command> [flags]
 
 Commands:
+  init                   bootstrap the DI skeleton (and go.mod) in an empty module
   create-usecase         scaffold/extend a usecase package & method (interactive if no flags)
   create-handler         scaffold/extend an inbound HTTP handler & route (interactive if no flags)
   create-repository      scaffold/extend a postgres repository and wire into DI (interactive if no flags)
   create-outbound        scaffold/extend an outbound adapter (interactive if no flags)
+  create-crud            generate Create/Get/List/Update/Delete usecase, repository and routes for a resource
+  create-migration       create a timestamped SQL migration (golang-migrate or goose)
+  create-mapper          generate a field-by-field conversion between two DTOs into the usecase package
   add-repo-to-usecase    wire an existing repository into an existing usecase (interactive if no flags)
+  remove-usecase-method  remove a usecase method, its impl and DTOs (refuses while still called)
+  remove-handler-route   remove a route and its handler method
+  remove-repository      delete a repository package and unwire it from DI (refuses while injected)
+  remove-outbound        delete an outbound adapter package (refuses while imported)
+  remove-repo-from-usecase  reverse add-repo-to-usecase
+  rename                 rename a usecase/repo/outbound method or a package across every layer
+  apply                  generate everything declared in a YAML/JSON spec file (idempotent)
+  from-openapi           generate routes, usecase methods and DTO fields from an OpenAPI 3 document (idempotent)
+  undo                   restore the files changed by the last run(s) (--steps=N), refusing if edited since
+  export-templates       copy the built-in code templates into .ntaps/templates for customizing
+  doctor                 check go.mod, DI files and markers the generators rely on (exit 1 on problems)
+  list                   print usecases, routes, repositories, outbounds and repo injections (--format=table|json)
+
+Global flags:
+  --dry-run              run against an in-memory copy and print a unified diff instead of writing
 
 Interactive examples:
   ntaps create-usecase
@@ -44,10 +147,37 @@ Interactive examples:
   ntaps add-repo-to-usecase
 
 Flag examples:
+  ntaps init --module=github.com/acme/payment-service
   ntaps create-usecase --pkg=send --method=SubmitCashToCash --withParam --withResponse
+  ntaps create-usecase --pkg=user --method=Register --withParam --field email:string:required,email --field name:string
   ntaps create-handler --pkg=send --ucPkg=send --endpointType=private --endpoint=/transaction/:transaction_code --withParamUc --withResponseUc --ucMethodName=GetTransactionDetailByCode --method=getTransactionDetailByCode --tag=Send --verb=GET
+  ntaps create-handler --pkg=order --ucPkg=order --endpoint=/:id --verb=PATCH --status=202 --withParamUc --ucMethodName=PatchOrder --method=patchOrder
+  ntaps create-handler --pkg=order --ucPkg=order --endpoint=/items --verb=GET --withParamUc --ucMethodName=ListItems --method=listItems --query page:int,limit:int --header X-Request-ID:string
+  ntaps create-handler --pkg=media --ucPkg=media --endpoint=/avatar --withParamUc --ucMethodName=UploadAvatar --method=uploadAvatar --upload='avatar,maxSize=5MB,types=image/png|image/jpeg'
+  ntaps create-handler --pkg=report --ucPkg=report --endpoint=/orders.csv --verb=GET --withParamUc --withResponseUc --ucMethodName=ExportOrders --method=exportOrders --download=csv
+  ntaps create-handler --pkg=order --ucPkg=order --endpoint=/list --verb=GET --withParamUc --withResponseUc --ucMethodName=ListOrders --method=listOrders --paginated --withRepo
+  ntaps create-usecase --pkg=order --method=Feed --withParam --withResponse --paginated=cursor
+  ntaps create-handler --pkg=user --ucPkg=user --endpoint=/register --withParamUc --ucMethodName=Register --method=register --field email:string --validate email:required,email
   ntaps create-repository --type=postgres --pkg=user --method=UpdateUserStatus --withParamRepo --withResponseRepo --withTx --addToUC=send
+  ntaps create-repository --pkg=user --method=ListActiveUsers --withResponseRepo --many --sqlStub
+  ntaps create-repository --pkg=user --fromQuery=GetUserByID --addToUC=send
+  ntaps create-repository --pkg=wallet --method=GetWallet --withParamRepo --withResponseRepo --withTable
+  ntaps create-repository --pkg=user --method=CreateUser --fromTable=users
+  ntaps create-migration --name=add_status_to_users --format=goose
+  ntaps create-mapper --from=usecase/send.SubmitRequest --to=repo/user.UpdateUserStatusParam --inMethod=Submit
   ntaps create-outbound --pkg=email --method=SendEmailActivation --withParam --withResp
-  ntaps add-repo-to-usecase --repoPkg=example --ucPkg=send --method=GetExample --withParamRepo --withResponseRepo --withTx`)
+  ntaps create-crud --resource=customer --fields="name:string,email:string,status:int" --endpointType=private
+  ntaps add-repo-to-usecase --repoPkg=example --ucPkg=send --method=GetExample --withParamRepo --withResponseRepo --withTx
+  ntaps remove-handler-route --pkg=send --method=submitCashToCash
+  ntaps remove-usecase-method --pkg=send --method=SubmitCashToCash
+  ntaps remove-repo-from-usecase --repoPkg=user --ucPkg=send
+  ntaps rename --kind=usecase-method --pkg=send --from=SubmitCashToCash --to=SubmitTransfer
+  ntaps rename --kind=package --from=send --to=transfer
+  ntaps apply -f service.yaml
+  ntaps from-openapi --spec=api.yaml
+  ntaps undo --steps=2
+  ntaps doctor --strict
+  ntaps list --format=json
+  ntaps --dry-run create-usecase --pkg=send --method=SubmitCashToCash --withParam`)
 	os.Exit(2)
 }
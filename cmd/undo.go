@@ -0,0 +1,30 @@
+package cmd
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/AndreeJait/ntaps/internal/journal"
+)
+
+func runUndoCmd(args []string) {
+	fs := flag.NewFlagSet("undo", flag.ExitOnError)
+
+	var steps int
+	fs.IntVar(&steps, "steps", 1, "number of recorded runs to undo, newest first")
+	_ = fs.Parse(args)
+
+	undone, err := journal.Undo(steps)
+	if err != nil {
+		exitErr(err.Error())
+	}
+
+	for _, e := range undone {
+		fmt.Println("•", e)
+		for _, f := range e.Files {
+			fmt.Println("    ", f.Path)
+		}
+	}
+	afterCommit(func() error { return journal.Drop(steps) })
+	fmt.Printf("✅ Done: undid %d run(s)\n", len(undone))
+}
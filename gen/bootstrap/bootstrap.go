@@ -0,0 +1,87 @@
+package bootstrap
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/AndreeJait/ntaps/internal/paths"
+	"github.com/AndreeJait/ntaps/internal/tmpl"
+	"github.com/AndreeJait/ntaps/internal/util"
+)
+
+// Run creates the DI skeleton the generators rely on (wire struct,
+// initUseCase/initRepository, the handlers slice, the http.Handler
+// interface, config/db/middleware stubs, a placeholder sqlc package) plus
+// go.mod when it is missing.
+// Existing files are never overwritten, so running it twice is harmless.
+func Run(module string) error {
+	if _, err := util.Stat("go.mod"); os.IsNotExist(err) {
+		if module == "" {
+			return fmt.Errorf("go.mod not found: pass --module=<module path> to create it")
+		}
+		if err := util.WriteFile("go.mod", fmt.Sprintf("module %s\n\ngo %s\n", module, goVersion())); err != nil {
+			return err
+		}
+		fmt.Println("• go.mod")
+	} else if module != "" && module != util.ModulePathGuess() {
+		fmt.Printf("ℹ️  go.mod already declares module %s; ignoring --module=%s\n", util.ModulePathGuess(), module)
+	}
+
+	data := tmpl.Bootstrap{
+		Module:                 util.ModulePathGuess(),
+		Receiver:               paths.WireReceiver,
+		ConfigImport:           util.ImportPath(paths.ConfigDir),
+		DBImport:               util.ImportPath(paths.InfraDBDir),
+		HTTPImport:             util.ImportPath(paths.HandlerRootHTTPDir),
+		MiddlewareImport:       util.ImportPath(paths.MiddlewareDir),
+		UsecaseImport:          util.ImportPath(filepath.Dir(paths.UsecaseDIPath)),
+		RepositoryImport:       util.ImportPath(filepath.Dir(paths.PgDiPath)),
+		UsecaseInitMarker:      paths.UsecaseInitMarker,
+		RepositoryInitMarker:   paths.RepositoryInitMarker,
+		HandlersSliceMarker:    paths.HandlersSliceMarker,
+		UsecaseStructMarker:    paths.UsecaseStructMarker,
+		RepositoryStructMarker: paths.RepositoryStructMarker,
+	}
+
+	files := []struct{ path, template string }{
+		{filepath.Join(filepath.Dir(paths.InfraInitUsecasePath), "wire.go"), "init_wire.tmpl"},
+		{paths.InfraInitUsecasePath, "init_usecase.tmpl"},
+		{paths.InfraRepoInitPath, "init_repository.tmpl"},
+		{paths.HandlerInfraInitPath, "init_handler.tmpl"},
+		{filepath.Join(paths.HandlerRootHTTPDir, "handler.go"), "init_http_handler.tmpl"},
+		{filepath.Join(paths.MiddlewareDir, "middleware.go"), "init_middleware.tmpl"},
+		{filepath.Join(paths.ConfigDir, "config.go"), "init_config.tmpl"},
+		{filepath.Join(paths.InfraDBDir, "tx.go"), "init_db.tmpl"},
+		{paths.UsecaseDIPath, "init_usecase_di.tmpl"},
+		{paths.PgDiPath, "init_repo_di.tmpl"},
+		{filepath.Join(paths.SqlcDir, "db.go"), "init_sqlc.tmpl"},
+	}
+	for _, f := range files {
+		if _, err := util.Stat(f.path); err == nil {
+			fmt.Printf("ℹ️  %s exists, keeping it\n", f.path)
+			continue
+		}
+		body, err := tmpl.Render(f.template, data)
+		if err != nil {
+			return err
+		}
+		if err := util.WriteGoFile(f.path, body); err != nil {
+			return err
+		}
+		fmt.Println("•", f.path)
+	}
+	return nil
+}
+
+// goVersion returns the running toolchain as a go.mod version, e.g. 1.23.
+func goVersion() string {
+	v := strings.TrimPrefix(runtime.Version(), "go")
+	parts := strings.SplitN(v, ".", 3)
+	if len(parts) < 2 {
+		return "1.22"
+	}
+	return parts[0] + "." + strings.TrimFunc(parts[1], func(r rune) bool { return r < '0' || r > '9' })
+}
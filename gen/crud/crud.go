@@ -0,0 +1,142 @@
+// Package crud generates a full Create/Get/List/Update/Delete resource:
+// usecase methods with typed DTOs, a postgres repository wired into the
+// usecase, and five Echo routes. It only composes the other generators, so
+// re-running it is as idempotent as they are.
+package crud
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/AndreeJait/ntaps/gen/handler"
+	"github.com/AndreeJait/ntaps/gen/repo"
+	"github.com/AndreeJait/ntaps/gen/usecase"
+	"github.com/AndreeJait/ntaps/internal/paths"
+	"github.com/AndreeJait/ntaps/internal/util"
+)
+
+// op is one of the five CRUD operations.
+type op struct {
+	method   string // usecase + repo method, e.g. GetCustomer
+	handler  string // handler method, e.g. getCustomer
+	verb     string
+	endpoint string
+	param    bool
+	resp     bool
+	byID     bool // request carries the :id path param
+	list     bool // response is Items []<Resource>Item
+	body     bool // request carries the resource fields
+}
+
+func ops(resource string) []op {
+	p := util.ToPascalCase(resource)
+	ps := util.Plural(p)
+	return []op{
+		{method: "Create" + p, verb: "POST", endpoint: "", param: true, resp: true, body: true},
+		{method: "Get" + p, verb: "GET", endpoint: "/:id", param: true, resp: true, byID: true},
+		{method: "List" + ps, verb: "GET", endpoint: "", resp: true, list: true},
+		{method: "Update" + p, verb: "PUT", endpoint: "/:id", param: true, resp: true, byID: true, body: true},
+		{method: "Delete" + p, verb: "DELETE", endpoint: "/:id", param: true, byID: true},
+	}
+}
+
+// Run generates the resource in package <resource> for every layer.
+func Run(resource string, fields []util.Field, endpointType, tag string) error {
+	pkg := strings.ToLower(resource)
+	pascal := util.ToPascalCase(resource)
+	if tag == "" {
+		tag = pascal
+	}
+
+	ucDTO := filepath.Join(paths.RootUsecaseDir, pkg, "dto.go")
+	repoDTO := filepath.Join(paths.RepoPgPath, pkg, "dto.go")
+
+	for _, o := range ops(resource) {
+		o.handler = strings.ToLower(o.method[:1]) + o.method[1:]
+
+		// 1) usecase method + typed DTOs
+		fmt.Printf("• usecase %s.%s\n", pkg, o.method)
+		if err := usecase.Run(pkg, o.method, o.param, o.resp); err != nil {
+			return err
+		}
+		if o.param {
+			if err := usecase.EnsureDTOType(ucDTO, pkg, o.method+"Request", requestFields(o, fields, "json")); err != nil {
+				return err
+			}
+		}
+		if o.resp {
+			if err := fillResponse(ucDTO, pkg, pascal+"Item", o, fields, "json"); err != nil {
+				return err
+			}
+		}
+
+		// 2) repository method, wired into the usecase
+		fmt.Printf("• repository %s.%s\n", pkg, o.method)
+		if err := repo.AddRepoToUsecase(pkg, pkg, o.method, o.param, o.resp, false); err != nil {
+			return err
+		}
+		if o.param {
+			if err := usecase.EnsureDTOType(repoDTO, pkg, o.method+"Param", requestFields(o, fields, "db")); err != nil {
+				return err
+			}
+		}
+		if o.resp {
+			if err := fillResponse(repoDTO, pkg, pascal+"Row", o, fields, "db"); err != nil {
+				return err
+			}
+		}
+
+		// 3) route
+		fmt.Printf("• route %s %s → %s.%s\n", o.verb, util.RouterPath(pkg, endpointType, o.endpoint), pkg, o.method)
+		if err := handler.Run(pkg, pkg, endpointType, o.endpoint, o.param, o.resp, o.method, o.handler, tag, o.verb, 0, nil, ""); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// requestFields are the fields of a Request/Param DTO; by-id operations get
+// the id from the path (usecase) or as a column (repo).
+func requestFields(o op, fields []util.Field, tagKey string) []util.StructField {
+	var out []util.StructField
+	if o.byID {
+		idTag := `param:"id"`
+		if tagKey == "db" {
+			idTag = `db:"id"`
+		}
+		out = append(out, util.StructField{Name: "ID", Type: "int64", Tag: idTag})
+	}
+	if o.body && tagKey == "json" { // validate rules apply to the incoming request
+		out = append(out, util.TagFields(fields, tagKey)...)
+	} else if o.body {
+		out = append(out, structFields(fields, tagKey)...)
+	}
+	return out
+}
+
+// fillResponse fills <Method>Response; list responses wrap the shared
+// item type, which is created on first use.
+func fillResponse(path, pkg, itemType string, o op, fields []util.Field, tagKey string) error {
+	row := append([]util.StructField{{Name: "ID", Type: "int64", Tag: tagKey + `:"id"`}}, structFields(fields, tagKey)...)
+	if !o.list {
+		return usecase.EnsureDTOType(path, pkg, o.method+"Response", row)
+	}
+	if err := usecase.EnsureDTOType(path, pkg, itemType, row); err != nil {
+		return err
+	}
+	items := util.StructField{Name: "Items", Type: "[]" + itemType}
+	if tagKey == "json" {
+		items.Tag = `json:"items"`
+	}
+	return usecase.EnsureDTOType(path, pkg, o.method+"Response", []util.StructField{items})
+}
+
+func structFields(fields []util.Field, tagKey string) []util.StructField {
+	out := make([]util.StructField, 0, len(fields))
+	for _, f := range fields {
+		f.Rules = ""
+		out = append(out, f.Tagged(tagKey))
+	}
+	return out
+}
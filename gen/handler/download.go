@@ -0,0 +1,24 @@
+package handler
+
+import (
+	"github.com/AndreeJait/ntaps/internal/util"
+)
+
+// Downloads are the --download modes: csv streams what the usecase
+// writes, file streams a reader it opened.
+var Downloads = []string{"csv", "file"}
+
+// DownloadFields are the Response fields a download handler streams from.
+func DownloadFields(kind string) []util.StructField {
+	if kind == "csv" {
+		return []util.StructField{
+			{Name: "FileName", Type: "string"},
+			{Name: "Write", Type: "func(w io.Writer) error"},
+		}
+	}
+	return []util.StructField{
+		{Name: "FileName", Type: "string"},
+		{Name: "ContentType", Type: "string"},
+		{Name: "Body", Type: "io.ReadCloser"},
+	}
+}
@@ -2,9 +2,7 @@ package handler
 
 import (
 	"fmt"
-	"os"
 	"path/filepath"
-	"strings"
 
 	"github.com/AndreeJait/ntaps/internal/paths"
 	"github.com/AndreeJait/ntaps/internal/util"
@@ -20,49 +18,50 @@ func ensureRequestDTOHasPathParams(
 
 	dtoPath := filepath.Join(paths.RootUsecaseDir, ucPkg, "dto.go")
 
-	raw, err := os.ReadFile(dtoPath)
+	raw, err := util.ReadFile(dtoPath)
 	if err != nil {
 		return err
 	}
-	src := string(raw)
 
-	typeName := ucMethodName + "Request"
-
-	// if dto.go doesn't have the Request type at all, we can't safely edit automatically
-	typeDeclStart := strings.Index(src, "type "+typeName+" struct")
-	if typeDeclStart == -1 {
-		return nil
+	var fields []util.StructField
+	for _, p := range pathParams {
+		fields = append(fields, util.StructField{
+			Name: util.ToPascalCase(p), // transaction_code -> TransactionCode
+			Type: "string",
+			Tag:  fmt.Sprintf(`param:"%s"`, p),
+		})
 	}
 
-	// find the closing "}" of that struct
-	braceOpen := strings.Index(src[typeDeclStart:], "{")
-	if braceOpen == -1 {
+	src, err := util.EnsureStructFields(string(raw), ucMethodName+"Request", fields)
+	if err != nil {
+		// if dto.go doesn't have the Request type at all, we can't safely edit automatically
 		return nil
 	}
-	braceOpen += typeDeclStart
+	return util.WriteGoFile(dtoPath, src)
+}
 
-	braceClose := strings.Index(src[braceOpen:], "}")
-	if braceClose == -1 {
-		return nil
+// RequestFields tags --field values for a route's Request: path params get
+// `param`, GET/HEAD/DELETE fields `query`, multipart (upload) fields `form`
+// and everything else `json`.
+func RequestFields(fields []util.Field, verb, endpoint string, multipart bool) []util.StructField {
+	_, params := normalizePathParams(endpoint)
+	inPath := map[string]bool{}
+	for _, p := range params {
+		inPath[p] = true
 	}
-	braceClose += braceOpen
 
-	structBody := src[braceOpen:braceClose] // includes leading "{\n..."
-
-	updatedBody := structBody
-
-	for _, p := range pathParams {
-		fieldName := util.ToPascalCase(p) // transaction_code -> TransactionCode
-		fieldLine := fmt.Sprintf("\n\t%s string `param:\"%s\"`", fieldName, p)
-
-		// only add if not present
-		if !strings.Contains(updatedBody, fieldName+" ") {
-			updatedBody = updatedBody + fieldLine
+	out := make([]util.StructField, 0, len(fields))
+	for _, f := range fields {
+		key := "json"
+		switch {
+		case inPath[f.Name]:
+			key = "param"
+		case queryVerb(verb):
+			key = "query"
+		case multipart:
+			key = "form"
 		}
+		out = append(out, f.Tagged(key))
 	}
-
-	// rebuild full file content
-	src = src[:braceOpen] + updatedBody + src[braceClose:]
-
-	return util.WriteGoFile(dtoPath, src)
+	return out
 }
@@ -1,10 +1,15 @@
 package handler
 
-// Run is the full flow: ensure usecase exists, ensure handler pkg, add method+route, wire DI.
 import (
 	"github.com/AndreeJait/ntaps/gen/usecase"
+	"github.com/AndreeJait/ntaps/internal/tmpl"
 )
 
+// Run is the full flow: ensure usecase exists, ensure handler pkg, add method+route, wire DI.
+// status is the success status of a new method; 0 picks DefaultStatus.
+// upload, when set, makes the new method read that multipart file into
+// the Request (see UploadFields); download (csv|file) makes it stream the
+// Response (see DownloadFields).
 func Run(
 	pkg string,
 	ucPkg string,
@@ -16,11 +21,24 @@ func Run(
 	handlerMethod string,
 	tag string,
 	verb string,
+	status int,
+	upload *tmpl.HandlerUpload,
+	download string,
 ) error {
+	if status == 0 {
+		status = DefaultStatus(verb, withResponseUc)
+	}
+	if err := CheckStatus(status, withResponseUc); err != nil {
+		return err
+	}
+
 	// 1) make sure the usecase + method exist
 	if err := usecase.Run(ucPkg, ucMethodName, withParamUc, withResponseUc); err != nil {
 		return err
 	}
+	if err := usecase.SyncValidators(ucPkg); err != nil {
+		return err
+	}
 
 	// 2) ensure handler pkg skeleton
 	if err := ensurePackageOnly(pkg); err != nil {
@@ -39,6 +57,9 @@ func Run(
 		withResponseUc,
 		tag,
 		verb,
+		status,
+		upload,
+		download,
 	); err != nil {
 		return err
 	}
@@ -2,11 +2,13 @@ package handler
 
 import (
 	"fmt"
-	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
 
+	"github.com/AndreeJait/ntaps/gen/usecase"
 	"github.com/AndreeJait/ntaps/internal/paths"
+	"github.com/AndreeJait/ntaps/internal/tmpl"
 	"github.com/AndreeJait/ntaps/internal/util"
 )
 
@@ -21,11 +23,13 @@ func ensureMethodAndRoute(
 	withResponseUc bool,
 	tag string,
 	verb string,
+	status int,
+	upload *tmpl.HandlerUpload,
+	download string,
 ) error {
-	mod := util.ModulePathGuess()
 	path := filepath.Join(paths.HandlerRootHTTPDir, pkg, paths.HandlerPkgFileName)
 
-	raw, err := os.ReadFile(path)
+	raw, err := util.ReadFile(path)
 	if err != nil {
 		return err
 	}
@@ -33,7 +37,7 @@ func ensureMethodAndRoute(
 
 	// imports needed
 	requiredImports := []string{
-		fmt.Sprintf(`"%s/internal/usecase/%s"`, mod, ucPkg),
+		fmt.Sprintf(`"%s"`, util.ImportPath(paths.RootUsecaseDir, ucPkg)),
 		`"github.com/AndreeJait/go-utility/response"`,
 		`"github.com/AndreeJait/go-utility/tracer"`,
 		`"github.com/labstack/echo/v4"`,
@@ -56,11 +60,11 @@ func ensureMethodAndRoute(
 	routeLine := fmt.Sprintf(`%s.%s("%s", h.%s)`, groupName, verbUpper, endpoint, handlerMethod)
 	routeLineTabbed := "\t" + routeLine
 
-	if !strings.Contains(src, "func (h *handler) Handle()") {
-		return fmt.Errorf("Handle() not found in %s", path)
+	if !util.MarkerRegexp(paths.HandleFuncMarker).MatchString(src) {
+		return fmt.Errorf("%q not found in %s", paths.HandleFuncMarker, path)
 	}
 	if !strings.Contains(src, routeLine) && !strings.Contains(src, routeLineTabbed) {
-		marker := "// ntaps:routes"
+		marker := paths.RoutesMarker
 		if idx := strings.Index(src, marker); idx != -1 {
 			src = src[:idx] + routeLine + "\n\t" + src[idx:]
 		} else {
@@ -69,10 +73,46 @@ func ensureMethodAndRoute(
 		}
 	}
 
+	// NEW FEATURE:
+	// If this is a GET withParamUc and the path has params, update the <UcMethodName>Request DTO
+	if strings.EqualFold(verbUpper, "GET") && withParamUc {
+		_, pathParams := normalizePathParams(endpoint)
+		if len(pathParams) > 0 {
+			if err := ensureRequestDTOHasPathParams(
+				ucPkg,
+				ucMethodName,
+				pathParams,
+			); err != nil {
+				// non-fatal: we still write handler; just surface the error
+				fmt.Println("ℹ️  warning: could not enrich DTO with path params:", err)
+			}
+		}
+	}
+
+	// query/header/cookie fields and a generated Validate() need more
+	// than c.Bind
+	var req request
+	if withParamUc {
+		req = loadRequest(ucPkg, ucMethodName+"Request")
+		req.Validate = usecase.HasValidator(ucPkg, ucMethodName+"Request")
+		if upload != nil {
+			req.Body = false // multipart: fields are documented as formData
+		}
+	}
+
 	// ensure method body exists
 	methodSig := fmt.Sprintf("func (h *handler) %s(", handlerMethod)
-	if !strings.Contains(src, methodSig) {
-		methodCode := buildHandlerMethod(
+	if strings.Contains(src, methodSig) {
+		if upload != nil {
+			fmt.Printf("ℹ️  %s already exists; add the %s upload handling by hand\n", handlerMethod, upload.Name)
+		}
+		if download != "" {
+			fmt.Printf("ℹ️  %s already exists; switch it to a %s download by hand\n", handlerMethod, download)
+		}
+		src = ensureBinds(src, methodSig, bindLines(req, verbUpper))
+		src = ensureParamDocs(src, handlerMethod, paramLines(req))
+	} else {
+		methodCode, err := buildHandlerMethod(
 			handlerMethod,
 			ucPkg,
 			ucMethodName,
@@ -82,59 +122,112 @@ func ensureMethodAndRoute(
 			withParamUc,
 			withResponseUc,
 			tag,
+			req,
+			status,
+			upload,
+			download,
 		)
+		if err != nil {
+			return err
+		}
+		if status != 200 || upload != nil || download != "" {
+			src = util.InsertImport(src, `nethttp "net/http"`) // dropped by goimports when unused
+		}
 		src += methodCode
 	}
 
-	// NEW FEATURE:
-	// If this is a GET withParamUc and the path has params, update the <UcMethodName>Request DTO
-	if strings.EqualFold(verbUpper, "GET") && withParamUc {
-		_, pathParams := normalizePathParams(endpoint)
-		if len(pathParams) > 0 {
-			if err := ensureRequestDTOHasPathParams(
-				ucPkg,
-				ucMethodName,
-				pathParams,
-			); err != nil {
-				// non-fatal: we still write handler; just surface the error
-				fmt.Println("ℹ️  warning: could not enrich DTO with path params:", err)
+	return util.WriteGoFile(path, src)
+}
+
+// ensureBinds adds the missing statements of lines after the Bind of an
+// existing handler method, keeping their order. A statement is recognized
+// by its header (the part before " { "), which gofmt leaves alone.
+func ensureBinds(src, methodSig string, lines []string) string {
+	start := strings.Index(src, methodSig)
+	end := strings.Index(src[start:], "\n}")
+	if end == -1 || len(lines) == 0 {
+		return src
+	}
+	end += start
+	bind := bindRe.FindStringIndex(src[start:end])
+	if bind == nil {
+		return src
+	}
+	at := start + bind[1]
+	for _, line := range lines {
+		head, _, _ := strings.Cut(line, " { ")
+		if i := strings.Index(src[at:end], head); i != -1 {
+			// already there: continue after its closing brace
+			if j := strings.Index(src[at+i:end], "\n\t}"); j != -1 {
+				at += i + j + len("\n\t}")
 			}
+			continue
 		}
+		add := "\n\t" + line
+		src = src[:at] + add + src[at:]
+		at += len(add)
+		end += len(add)
 	}
+	return src
+}
 
-	return util.WriteGoFile(path, src)
+// ensureParamDocs adds missing @Param lines to an existing handler
+// method's swagger block, above its @Success line.
+func ensureParamDocs(src, handlerMethod string, lines []string) string {
+	start := strings.Index(src, "// "+handlerMethod+" godoc\n")
+	if start == -1 || len(lines) == 0 {
+		return src
+	}
+	end := strings.Index(src[start:], "\nfunc ")
+	if end == -1 {
+		return src
+	}
+	end += start
+	at := strings.Index(src[start:end], "// @Success")
+	if at == -1 {
+		return src
+	}
+	at += start
+	var add string
+	for _, line := range lines {
+		name := strings.Fields(line)[2]
+		if !regexp.MustCompile(`// @Param\s+` + regexp.QuoteMeta(name) + `\s`).MatchString(src[start:end]) {
+			add += line + "\n"
+		}
+	}
+	return src[:at] + add + src[at:]
 }
 
+var bindRe = regexp.MustCompile(`if err := c\.Bind\(&param\); err != nil \{\s*return err\s*\}`)
+
 func updateInfraHandlerInit(pkg string) error {
-	mod := util.ModulePathGuess()
 	path := paths.HandlerInfraInitPath
 
-	raw, err := os.ReadFile(path)
+	raw, err := util.ReadFile(path)
 	if err != nil {
 		return fmt.Errorf("cannot find %s to register handler", path)
 	}
 	src := string(raw)
 
-	// 1. ensure import "<module>/internal/adapters/inbound/http/<pkg>"
-	importLine := fmt.Sprintf(`"%s/internal/adapters/inbound/http/%s"`, mod, pkg)
+	// 1. ensure import "<module>/<handler dir>/<pkg>"
+	importLine := fmt.Sprintf(`"%s"`, util.ImportPath(paths.HandlerRootHTTPDir, pkg))
 	if !strings.Contains(src, importLine) {
 		src = util.InsertImport(src, importLine)
 	}
 
 	// 2. find handlers slice block: var handlers = []http.Handler{ ... }
-	openMarker := "var handlers = []http.Handler{"
-	start := strings.Index(src, openMarker)
-	if start == -1 {
-		return fmt.Errorf("handlers slice not found in %s", path)
+	loc := util.MarkerRegexp(paths.HandlersSliceMarker).FindStringIndex(src)
+	if loc == nil {
+		return fmt.Errorf("handlers slice %q not found in %s", paths.HandlersSliceMarker, path)
 	}
 
-	after := src[start+len(openMarker):] // content after "{"
+	after := src[loc[1]:] // content after "{"
 	endRel := strings.Index(after, "}")
 	if endRel == -1 {
 		return fmt.Errorf("handlers slice closing '}' not found in %s", path)
 	}
 
-	blockStart := start + len(openMarker)
+	blockStart := loc[1]
 	blockEnd := blockStart + endRel // position of '}'
 	block := src[blockStart:blockEnd]
 
@@ -146,11 +239,25 @@ func updateInfraHandlerInit(pkg string) error {
 		return util.WriteGoFile(path, src)
 	}
 
-	// 4. otherwise insert default call before the end of slice
-	newCall := fmt.Sprintf("\n\t\t%s.New%sHandler(s.cfg, groupV1, s.uc),",
+	// 4. otherwise insert default call before the end of slice, keeping
+	// the closing brace on its own line so gofmt leaves the trailing comma
+	newCall := fmt.Sprintf("\t\t%s.New%sHandler(%[3]s.cfg, groupV1, %[3]s.uc),\n\t",
 		pkg,
 		util.ToPascalCase(pkg),
+		paths.WireReceiver,
 	)
+	prev := strings.TrimRight(block, " \t\n")
+	if prev != "" && !strings.HasSuffix(prev, ",") {
+		// a previous entry lost its comma
+		src = src[:blockStart+len(prev)] + "," + src[blockStart+len(prev):]
+		blockEnd++
+		block = src[blockStart:blockEnd]
+	}
+	if i := strings.LastIndex(block, "\n"); i != -1 {
+		blockEnd = blockStart + i + 1 // start of the closing brace's line
+	} else {
+		newCall = "\n" + newCall
+	}
 
 	src = src[:blockEnd] + newCall + src[blockEnd:]
 
@@ -0,0 +1,147 @@
+package handler
+
+import (
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/AndreeJait/ntaps/internal/paths"
+	"github.com/AndreeJait/ntaps/internal/tmpl"
+	"github.com/AndreeJait/ntaps/internal/util"
+)
+
+// ParamSources are the Request field sources besides the path and body;
+// each is also the struct tag key of its fields.
+var ParamSources = []string{"query", "header", "cookie"}
+
+// CheckCookieFields rejects non-string cookies: they are copied from
+// http.Cookie.Value as is.
+func CheckCookieFields(fields []util.Field) error {
+	for _, f := range fields {
+		if f.Type != "string" {
+			return fmt.Errorf("cookie %s must be a string, got %s", f.Name, f.Type)
+		}
+	}
+	return nil
+}
+
+// request is what the handler needs to know about the usecase Request.
+type request struct {
+	Params   []tmpl.HandlerParam // query/header/cookie/form fields
+	Path     map[string]string   // swagger type per path param, e.g. id: integer
+	Body     bool                // has json fields, or no tagged fields at all
+	Init     string              // default tags as literal fields, e.g. "Limit: 20"
+	Validate bool                // has a generated Validate()
+}
+
+// loadRequest reads the Request DTO's tags from the usecase dto.go.
+func loadRequest(ucPkg, typeName string) request {
+	var req request
+	raw, err := util.ReadFile(filepath.Join(paths.RootUsecaseDir, ucPkg, "dto.go"))
+	if err != nil {
+		return req
+	}
+	tagged := false
+	var inits []string
+	for _, fl := range util.StructTags(string(raw), typeName) {
+		tag := reflect.StructTag(fl.Tag)
+		if v, _, _ := strings.Cut(tag.Get("json"), ","); v != "" && v != "-" {
+			req.Body = true
+		}
+		if name := tag.Get("param"); name != "" {
+			tagged = true
+			if req.Path == nil {
+				req.Path = map[string]string{}
+			}
+			req.Path[name] = swaggerType(fl.Type)
+		}
+		for _, in := range []string{"query", "header", "cookie", "form"} {
+			name, _, _ := strings.Cut(tag.Get(in), ",")
+			if name == "" || name == "-" {
+				continue
+			}
+			if in == "form" {
+				in = "formData"
+			}
+			req.Params = append(req.Params, tmpl.HandlerParam{
+				Name:     name,
+				In:       in,
+				Field:    fl.Name,
+				Type:     swaggerType(fl.Type),
+				Required: strings.Contains(","+tag.Get("validate")+",", ",required,"),
+				Default:  tag.Get("default"),
+			})
+		}
+		// Bind keeps what the client did not send, so defaults go in first
+		if def := tag.Get("default"); def != "" {
+			if fl.Type == "string" {
+				def = strconv.Quote(def)
+			}
+			inits = append(inits, fl.Name+": "+def)
+		}
+	}
+	req.Init = strings.Join(inits, ", ")
+	if !req.Body && !tagged && len(req.Params) == 0 {
+		req.Body = true // untyped Request: document it whole, as before
+	}
+	return req
+}
+
+// swaggerType maps a Go field type to a swag @Param data type.
+func swaggerType(typ string) string {
+	typ = strings.TrimPrefix(typ, "*")
+	if strings.HasPrefix(typ, "[]") && typ != "[]byte" {
+		return "[]" + swaggerType(typ[2:])
+	}
+	switch typ {
+	case "int", "int8", "int16", "int32", "int64",
+		"uint", "uint8", "uint16", "uint32", "uint64":
+		return "integer"
+	case "float32", "float64":
+		return "number"
+	case "bool":
+		return "boolean"
+	}
+	return "string"
+}
+
+// paramLines are the swagger @Param lines of req's query/header/cookie fields.
+func paramLines(req request) []string {
+	var out []string
+	for _, p := range req.Params {
+		line := fmt.Sprintf(`// @Param        %s %s %s %v "%s"`, p.Name, p.In, p.Type, p.Required, p.Name)
+		if p.Default != "" {
+			line += " default(" + p.Default + ")"
+		}
+		out = append(out, line)
+	}
+	return out
+}
+
+// bindLines are the statements after c.Bind that fill what Bind skips:
+// query params on body verbs, headers and cookies, then Validate().
+func bindLines(req request, verb string) []string {
+	var out []string
+	query, header := false, false
+	for _, p := range req.Params {
+		query = query || p.In == "query"
+		header = header || p.In == "header"
+	}
+	if query && !queryVerb(verb) {
+		out = append(out, "if err := (&echo.DefaultBinder{}).BindQueryParams(c, &param); err != nil { return err }")
+	}
+	if header {
+		out = append(out, "if err := (&echo.DefaultBinder{}).BindHeaders(c, &param); err != nil { return err }")
+	}
+	for _, p := range req.Params {
+		if p.In == "cookie" {
+			out = append(out, fmt.Sprintf(`if ck, err := c.Cookie(%q); err == nil { param.%s = ck.Value }`, p.Name, p.Field))
+		}
+	}
+	if req.Validate {
+		out = append(out, "if err := param.Validate(); err != nil { return echo.NewHTTPError(echo.ErrBadRequest.Code, err.Error()) }")
+	}
+	return out
+}
@@ -1,61 +1,36 @@
 package handler
 
 import (
-	"fmt"
 	"os"
 	"path/filepath"
 
 	"github.com/AndreeJait/ntaps/internal/paths"
+	"github.com/AndreeJait/ntaps/internal/tmpl"
 	"github.com/AndreeJait/ntaps/internal/util"
 )
 
 func ensurePackageOnly(pkg string) error {
-	mod := util.ModulePathGuess()
-
 	dir := filepath.Join(paths.HandlerRootHTTPDir, pkg)
-	if _, err := os.Stat(dir); os.IsNotExist(err) {
-		if err := os.MkdirAll(dir, 0o755); err != nil {
+	if _, err := util.Stat(dir); os.IsNotExist(err) {
+		if err := util.MkdirAll(dir); err != nil {
 			return err
 		}
 	}
 
 	path := filepath.Join(dir, paths.HandlerPkgFileName)
-	if _, err := os.Stat(path); os.IsNotExist(err) {
-		body := fmt.Sprintf(`package %s
-
-import (
-	http "%[2]s/internal/adapters/inbound/http"
-	"%[2]s/internal/adapters/inbound/http/common/middleware"
-	"%[2]s/internal/infrastructure/config"
-	"%[2]s/internal/usecase"
-
-	"github.com/AndreeJait/go-utility/response"
-	"github.com/AndreeJait/go-utility/tracer"
-	"github.com/labstack/echo/v4"
-)
-
-type handler struct {
-	route *echo.Group
-	uc    *usecase.UseCase
-	cfg   *config.Config
-}
-
-func New%[3]sHandler(cfg *config.Config, route *echo.Group, uc *usecase.UseCase) http.Handler {
-	return &handler{cfg: cfg, route: route, uc: uc}
-}
-
-// Handle registers routes for this module.
-func (h *handler) Handle() {
-	groupPublic := h.route.Group("/%[1]s")
-	groupInternal := h.route.Group("/internal/%[1]s")
-	groupPrivate := h.route.Group("/%[1]s")
-
-	groupInternal.Use(middleware.BasicAuthLogged(h.cfg))
-	groupPrivate.Use(middleware.MustLogged(h.cfg))
-
-	// ntaps:routes
-}
-`, pkg, mod, util.ToPascalCase(pkg))
+	if _, err := util.Stat(path); os.IsNotExist(err) {
+		body, err := tmpl.Render("handler_pkg.tmpl", tmpl.HandlerPkg{
+			Pkg:              pkg,
+			PkgPascal:        util.ToPascalCase(pkg),
+			HTTPImport:       util.ImportPath(paths.HandlerRootHTTPDir),
+			MiddlewareImport: util.ImportPath(paths.MiddlewareDir),
+			ConfigImport:     util.ImportPath(paths.ConfigDir),
+			UsecaseImport:    util.ImportPath(filepath.Dir(paths.UsecaseDIPath)),
+			RoutesMarker:     paths.RoutesMarker,
+		})
+		if err != nil {
+			return err
+		}
 		return util.WriteGoFile(path, body)
 	}
 
@@ -0,0 +1,52 @@
+package handler
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+
+	"github.com/AndreeJait/ntaps/internal/paths"
+	"github.com/AndreeJait/ntaps/internal/util"
+)
+
+// RemoveRoute reverses create-handler for one route: the
+// group.VERB("...", h.<method>) line in Handle() and the handler method
+// with its swagger block. The usecase method it calls is left alone.
+func RemoveRoute(pkg, handlerMethod string, force bool) error {
+	dir := filepath.Join(paths.HandlerRootHTTPDir, pkg)
+	path := filepath.Join(dir, paths.HandlerPkgFileName)
+
+	raw, err := util.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("handler %q not found: %w", pkg, err)
+	}
+	src := string(raw)
+
+	routeRe := regexp.MustCompile(`^\s*\w+\.[A-Z]+\("[^"]*",\s*h\.` + regexp.QuoteMeta(handlerMethod) + `\)\s*$`)
+	src, routes := util.RemoveLines(src, routeRe)
+	src, found := util.RemoveFunc(src, "handler", handlerMethod)
+	if routes == 0 && !found {
+		return fmt.Errorf("route/method %s not found in %s", handlerMethod, path)
+	}
+
+	// anything left calling h.<method> (in this file or elsewhere in the pkg)
+	useRe := regexp.MustCompile(`\bh\.` + regexp.QuoteMeta(handlerMethod) + `\b`)
+	refs := util.MatchLines(path, src, useRe)
+	files, err := util.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return err
+	}
+	for _, p := range files {
+		if p == path {
+			continue
+		}
+		if b, err := util.ReadFile(p); err == nil {
+			refs = append(refs, util.MatchLines(p, string(b), useRe)...)
+		}
+	}
+	if err := util.GuardReferences("h."+handlerMethod, refs, force); err != nil {
+		return err
+	}
+
+	return util.WriteGoFile(path, src)
+}
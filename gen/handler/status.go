@@ -0,0 +1,85 @@
+package handler
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Verbs are the HTTP verbs a route can use; each is an echo.Group method.
+var Verbs = []string{"GET", "POST", "PUT", "PATCH", "DELETE", "HEAD", "OPTIONS"}
+
+// IsVerb reports whether verb (upper-case) is one of Verbs.
+func IsVerb(verb string) bool {
+	for _, v := range Verbs {
+		if v == verb {
+			return true
+		}
+	}
+	return false
+}
+
+// queryVerb reports whether Echo binds the Request from the query string
+// for verb, as it does for GET, HEAD and DELETE.
+func queryVerb(verb string) bool {
+	switch strings.ToUpper(verb) {
+	case "GET", "HEAD", "DELETE":
+		return true
+	}
+	return false
+}
+
+// DefaultStatus is the success status of a new route: 201 for POST, 204
+// for a DELETE without Response, 200 otherwise.
+func DefaultStatus(verb string, withResponse bool) int {
+	switch strings.ToUpper(verb) {
+	case "POST":
+		return 201
+	case "DELETE":
+		if !withResponse {
+			return 204
+		}
+	}
+	return 200
+}
+
+// CheckStatus rejects non-2xx statuses and a bodiless one that would drop
+// a Response.
+func CheckStatus(status int, withResponse bool) error {
+	if status < 200 || status > 299 {
+		return fmt.Errorf("status %d is not a 2xx success status", status)
+	}
+	if noBody(status) && withResponse {
+		return fmt.Errorf("status %d has no body; drop the Response or pick another status", status)
+	}
+	return nil
+}
+
+// noBody reports whether status forbids a response body, so the handler
+// answers with c.NoContent instead of the response.Response envelope.
+func noBody(status int) bool {
+	return status == 204 || status == 205
+}
+
+// statusNames are the net/http constants of the 2xx statuses.
+var statusNames = map[int]string{
+	200: "StatusOK",
+	201: "StatusCreated",
+	202: "StatusAccepted",
+	203: "StatusNonAuthoritativeInfo",
+	204: "StatusNoContent",
+	205: "StatusResetContent",
+	206: "StatusPartialContent",
+	207: "StatusMultiStatus",
+	208: "StatusAlreadyReported",
+	226: "StatusIMUsed",
+}
+
+// statusExpr is status as Go source; net/http is imported as nethttp since
+// handler packages already import the project's own http package.
+func statusExpr(status int) string {
+	if name, ok := statusNames[status]; ok {
+		return "nethttp." + name
+	}
+	return strconv.Itoa(status)
+}
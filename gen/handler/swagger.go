@@ -5,6 +5,8 @@ import (
 	"regexp"
 	"strings"
 
+	"github.com/AndreeJait/ntaps/gen/usecase"
+	"github.com/AndreeJait/ntaps/internal/tmpl"
 	"github.com/AndreeJait/ntaps/internal/util"
 )
 
@@ -53,121 +55,72 @@ func buildHandlerMethod(
 	withParamUc bool,
 	withResponseUc bool,
 	tag string,
-) string {
+	req request,
+	status int,
+	upload *tmpl.HandlerUpload,
+	download string,
+) (string, error) {
 
 	// Security annotation
 	security := ""
 	switch strings.ToLower(endpointType) {
 	case "internal":
-		security = "// @Security BasicAuth\n"
+		security = "BasicAuth"
 	case "private":
-		security = "// @Security BearerAuth\n"
+		security = "BearerAuth"
 	}
 
-	human := util.HumanizePascal(ucMethodName)
-
-	// GET => request comes from query, others => body
+	// GET/HEAD/DELETE => request comes from query, others => body
 	paramLoc := "body"
-	if strings.EqualFold(httpVerb, "GET") {
+	if queryVerb(httpVerb) {
 		paramLoc = "query"
 	}
 
 	// Normalize /foo/:code -> /foo/{code} and collect ["code"]
 	normEndpoint, pathParams := normalizePathParams(endpoint)
 
-	// Path param swagger lines
-	// @Param transaction_code path string true "Transaction Code"
-	pathParamAnnots := ""
+	// path params are strings unless the Request types them, e.g. ID int64
+	pathTypes := map[string]string{}
 	for _, p := range pathParams {
-		pathParamAnnots += fmt.Sprintf(
-			`// @Param        %s path string true "%s"`+"\n",
-			p,
-			util.HumanizePascal(p),
-		)
-	}
-
-	// Body/query param swagger line (+ bind code)
-	paramAnnot := ""
-	paramLine := ""
-	callArgs := "ctx"
-
-	if withParamUc {
-		paramType := fmt.Sprintf("%s.%sRequest", ucPkg, ucMethodName)
-
-		// ✅ FIXED: single schema token transfer.InsertIntoManualTransferHistoriesRequest
-		paramAnnot = fmt.Sprintf(`// @Param       request %s %s true "%sRequest"
-`, paramLoc, paramType, ucMethodName)
-
-		paramLine = fmt.Sprintf(`
-	param := %s{}
-	if err := c.Bind(&param); err != nil { return err }
-`, paramType)
-
-		callArgs = "ctx, param"
+		pathTypes[p] = "string"
+		if t := req.Path[p]; t != "" {
+			pathTypes[p] = t
+		}
 	}
 
-	// Response wiring
-	respType := ""
-	returnOK := `return response.SuccessOK(c, nil, "success ` + strings.ToLower(human) + `")`
-
-	if withResponseUc {
-		respType = fmt.Sprintf("%s.%sResponse", ucPkg, ucMethodName)
-		returnOK = `return response.SuccessOK(c, resp, "success ` + strings.ToLower(human) + `")`
+	data := tmpl.HandlerMethod{
+		Method:       handlerMethod,
+		UcPkg:        ucPkg,
+		UcField:      util.ToPascalCase(ucPkg) + "Uc",
+		UcMethod:     ucMethodName,
+		Summary:      util.HumanizePascal(ucMethodName),
+		Tag:          tag,
+		Verb:         strings.ToUpper(httpVerb),
+		EndpointType: strings.ToLower(endpointType),
+		Security:     security,
+		// Swagger @Router path needs prefix (/pkg or /internal/pkg)
+		Route:        util.RouterPath(ucPkg, endpointType, normEndpoint),
+		PathParams:   pathParams,
+		PathTypes:    pathTypes,
+		WithParam:    withParamUc,
+		WithResponse: withResponseUc,
+		ParamIn:      paramLoc,
+		RequestType:  fmt.Sprintf("%s.%sRequest", ucPkg, ucMethodName),
+		ResponseType: fmt.Sprintf("%s.%sResponse", ucPkg, ucMethodName),
+		Params:       req.Params,
+		Body:         req.Body,
+		Init:         req.Init,
+		Binds:        bindLines(req, httpVerb),
+		Validate:     req.Validate,
+		Status:       status,
+		StatusExpr:   statusExpr(status),
+		NoBody:       noBody(status),
+		Upload:       upload,
+		Download:     download,
 	}
-
-	// Call the UC
-	var callLine string
-	if withResponseUc {
-		callLine = fmt.Sprintf(
-			"resp, err := h.uc.%sUc.%s(%s)",
-			util.ToPascalCase(ucPkg),
-			ucMethodName,
-			callArgs,
-		)
-	} else {
-		callLine = fmt.Sprintf(
-			"err := h.uc.%sUc.%s(%s)",
-			util.ToPascalCase(ucPkg),
-			ucMethodName,
-			callArgs,
-		)
+	if req.Validate {
+		data.Rules = usecase.ValidationSummary(ucPkg, ucMethodName+"Request")
 	}
 
-	// Swagger @Router path needs prefix (/pkg or /internal/pkg)
-	fullRoute := util.RouterPath(ucPkg, endpointType, normEndpoint)
-
-	return fmt.Sprintf(`
-
-// %s godoc
-// @Summary      %s
-// @Description  %s
-// @Tags         %s
-// @Accept       json
-// @Produce      json
-%s%s%s// @Success     200 {object} response.Response%s "success %s"
-// @Failure      400 {object} response.ErrorResponse "validation/bind error"
-// @Failure      500 {object} response.ErrorResponse "internal error"
-// @Router       %s [%s]
-func (h *handler) %s(c echo.Context) error {
-	ctx := c.Request().Context()
-	span, ctx := tracer.StartSpan(ctx, tracer.GetFuncName(h.%s))
-	defer span.End()%s
-
-	%s
-	if err != nil { return err }
-	%s
-}
-`,
-		handlerMethod,
-		human, human, tag,
-		security,
-		pathParamAnnots,
-		paramAnnot,
-		util.Conditional(withResponseUc, "{data="+respType+"}"),
-		strings.ToLower(human),
-		fullRoute,
-		strings.ToLower(httpVerb),
-		handlerMethod, handlerMethod, paramLine,
-		callLine, returnOK,
-	)
+	return tmpl.Render("handler_method.tmpl", data)
 }
@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/AndreeJait/ntaps/internal/tmpl"
+	"github.com/AndreeJait/ntaps/internal/util"
+)
+
+var (
+	uploadNameRe = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	sizeRe       = regexp.MustCompile(`^(?i)(\d+)\s*(b|kb|mb|gb)?$`)
+)
+
+// ParseUpload parses --upload "file[,maxSize=10MB,types=image/png|image/jpeg]".
+func ParseUpload(spec string) (*tmpl.HandlerUpload, error) {
+	parts := strings.Split(spec, ",")
+	name := strings.TrimSpace(parts[0])
+	if !uploadNameRe.MatchString(name) {
+		return nil, fmt.Errorf("upload form field %q is not an identifier", name)
+	}
+	up := &tmpl.HandlerUpload{Name: name, Field: util.ExportedName(name)}
+	for _, opt := range parts[1:] {
+		key, val, _ := strings.Cut(strings.TrimSpace(opt), "=")
+		switch key {
+		case "maxSize":
+			m := sizeRe.FindStringSubmatch(strings.TrimSpace(val))
+			if m == nil {
+				return nil, fmt.Errorf("maxSize %q: want a size like 512KB, 10MB or 1GB", val)
+			}
+			n, _ := strconv.ParseInt(m[1], 10, 64)
+			shift := map[string]uint{"": 0, "b": 0, "kb": 10, "mb": 20, "gb": 30}[strings.ToLower(m[2])]
+			up.MaxSize, up.MaxSizeText = n<<shift, strings.ToUpper(m[1]+m[2])
+			if shift == 0 {
+				up.MaxSizeText = m[1] + " bytes"
+			}
+		case "types":
+			for _, t := range strings.Split(val, "|") {
+				if t = strings.TrimSpace(t); t != "" {
+					if !strings.Contains(t, "/") {
+						return nil, fmt.Errorf("types: %q is not a MIME type", t)
+					}
+					up.Types = append(up.Types, t)
+				}
+			}
+		default:
+			return nil, fmt.Errorf("unknown upload option %q (use maxSize=, types=)", opt)
+		}
+	}
+	return up, nil
+}
+
+// UploadFields are the Request fields the handler fills from the upload:
+// the content as an io.Reader plus its name, size and content type.
+func UploadFields(up *tmpl.HandlerUpload) []util.StructField {
+	return []util.StructField{
+		{Name: up.Field, Type: "io.Reader"},
+		{Name: up.Field + "Name", Type: "string"},
+		{Name: up.Field + "Size", Type: "int64"},
+		{Name: up.Field + "Type", Type: "string"},
+	}
+}
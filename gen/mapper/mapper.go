@@ -0,0 +1,409 @@
+// Package mapper writes conversion functions between DTOs of different
+// layers (usecase Request -> repository Param, repository Response ->
+// usecase Response, ...) into the usecase package.
+package mapper
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/AndreeJait/ntaps/internal/paths"
+	"github.com/AndreeJait/ntaps/internal/tmpl"
+	"github.com/AndreeJait/ntaps/internal/util"
+)
+
+// Layers are the --from/--to prefixes and the directory each maps to.
+var Layers = map[string]*string{
+	"usecase":  &paths.RootUsecaseDir,
+	"repo":     &paths.RepoPgPath,
+	"outbound": &paths.OutboundRootPath,
+}
+
+var refRe = regexp.MustCompile(`^(\w+)/(\w+)\.(\w+)$`)
+
+// Ref is a DTO type reference such as usecase/send.SubmitRequest.
+type Ref struct {
+	Layer, Pkg, Type string
+}
+
+// ParseRef parses "<layer>/<pkg>.<Type>".
+func ParseRef(s string) (Ref, error) {
+	m := refRe.FindStringSubmatch(strings.TrimSpace(s))
+	if m == nil {
+		return Ref{}, fmt.Errorf("%q must be <layer>/<pkg>.<Type> (e.g. repo/user.UpdateUserStatusParam)", s)
+	}
+	if _, ok := Layers[m[1]]; !ok {
+		return Ref{}, fmt.Errorf("%q: layer must be usecase, repo or outbound", s)
+	}
+	return Ref{Layer: m[1], Pkg: m[2], Type: m[3]}, nil
+}
+
+func (r Ref) String() string { return r.Layer + "/" + r.Pkg + "." + r.Type }
+
+func (r Ref) dir() string { return filepath.Join(*Layers[r.Layer], r.Pkg) }
+
+// Result is what Run wrote.
+type Result struct {
+	Path   string   // mapper.go
+	Func   string   // conversion function name
+	Mapped []string // target fields filled
+	TODOs  []string // target fields left as TODO comments
+}
+
+// field is one struct field with its type as seen from the usecase pkg.
+type field struct {
+	name, typ string
+}
+
+// Run writes the function converting from into to into the usecase
+// package of from (or of to, when from is not a usecase type). With
+// inMethod, the call is also inserted into that usecase method, whose
+// request must be the from type.
+func Run(from, to Ref, inMethod string) (*Result, error) {
+	host := from
+	if host.Layer != "usecase" {
+		host = to
+	}
+	if host.Layer != "usecase" {
+		return nil, fmt.Errorf("one of --from/--to must be a usecase type (the mapper lives in the usecase package)")
+	}
+
+	imports := map[string]bool{}
+	src, err := loadStruct(from, host, imports)
+	if err != nil {
+		return nil, err
+	}
+	dst, err := loadStruct(to, host, imports)
+	if err != nil {
+		return nil, err
+	}
+
+	data := tmpl.Mapper{
+		From: typeName(from, host),
+		To:   typeName(to, host),
+	}
+	data.Func = funcName(from, to, host)
+	res := &Result{Path: filepath.Join(host.dir(), "mapper.go"), Func: data.Func}
+
+	// a field that cannot be mapped without losing its value is assigned
+	// as is, so the build fails on that line until it is done by hand
+	for _, d := range dst {
+		s, ok := match(src, d.name)
+		if !ok {
+			todo := tmpl.MapperField{Name: d.name, Expr: "in." + d.name, Note: fmt.Sprintf("no field %s in %s", d.name, data.From)}
+			data.TODOs = append(data.TODOs, todo)
+			res.TODOs = append(res.TODOs, fmt.Sprintf("%s %s: %s", d.name, d.typ, todo.Note))
+			continue
+		}
+		expr, ok := convert("in."+s.name, s.typ, d.typ, imports)
+		if !ok {
+			note := fmt.Sprintf("from %s %s", s.name, s.typ)
+			if isNumeric(s.typ) && isNumeric(d.typ) {
+				note += " may not fit; check the range and convert by hand"
+			}
+			data.TODOs = append(data.TODOs, tmpl.MapperField{Name: d.name, Expr: "in." + s.name, Note: note})
+			res.TODOs = append(res.TODOs, fmt.Sprintf("%s %s: %s", d.name, d.typ, note))
+			continue
+		}
+		data.Fields = append(data.Fields, tmpl.MapperField{Name: d.name, Expr: expr})
+		res.Mapped = append(res.Mapped, d.name)
+	}
+
+	if err := writeFunc(res.Path, host.Pkg, data, imports); err != nil {
+		return nil, err
+	}
+	if inMethod != "" {
+		if from.Layer != "usecase" || from.Pkg != host.Pkg {
+			return nil, fmt.Errorf("--inMethod needs --from to be a request of usecase %s", host.Pkg)
+		}
+		if err := insertCall(host.Pkg, inMethod, data, to.Type); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+// typeName is r as written inside the host package.
+func typeName(r, host Ref) string {
+	if r.Layer == host.Layer && r.Pkg == host.Pkg {
+		return r.Type
+	}
+	return r.Pkg + "." + r.Type
+}
+
+// funcName is e.g. submitRequestToUserUpdateUserStatusParam; types from
+// other packages carry their package name so both directions stay unique.
+func funcName(from, to, host Ref) string {
+	name := func(r Ref) string {
+		if r.Layer == host.Layer && r.Pkg == host.Pkg {
+			return r.Type
+		}
+		return util.ToPascalCase(r.Pkg) + r.Type
+	}
+	f := name(from)
+	return strings.ToLower(f[:1]) + f[1:] + "To" + name(to)
+}
+
+// loadStruct reads r's fields, qualifying their types for the host
+// package and recording the imports they need.
+func loadStruct(r, host Ref, imports map[string]bool) ([]field, error) {
+	files, _ := util.Glob(filepath.Join(r.dir(), "*.go"))
+	fset := token.NewFileSet()
+	for _, file := range files {
+		raw, err := util.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		f, err := parser.ParseFile(fset, file, raw, 0)
+		if err != nil {
+			return nil, fmt.Errorf("parse %s: %w", file, err)
+		}
+		var st *ast.StructType
+		ast.Inspect(f, func(n ast.Node) bool {
+			if ts, ok := n.(*ast.TypeSpec); ok && ts.Name.Name == r.Type {
+				st, _ = ts.Type.(*ast.StructType)
+				return false
+			}
+			return st == nil
+		})
+		if st == nil {
+			continue
+		}
+
+		fileImports := map[string]string{}
+		for _, imp := range f.Imports {
+			path, _ := strconv.Unquote(imp.Path.Value)
+			name := filepath.Base(path)
+			if v := filepath.Base(path); strings.HasPrefix(v, "v") && strings.Trim(v[1:], "0123456789") == "" {
+				name = filepath.Base(filepath.Dir(path)) // github.com/jackc/pgx/v5 -> pgx
+			}
+			if imp.Name != nil {
+				name = imp.Name.Name
+			}
+			fileImports[name] = path
+		}
+		q := qualifier{local: r.Pkg, imports: fileImports, used: imports}
+		if r.Layer != host.Layer || r.Pkg != host.Pkg {
+			q.qualify = true
+			imports[util.ImportPath(*Layers[r.Layer], r.Pkg)] = true
+		}
+
+		var out []field
+		for _, fld := range st.Fields.List {
+			typ := q.expr(fld.Type)
+			for _, n := range fld.Names {
+				if n.IsExported() {
+					out = append(out, field{name: n.Name, typ: typ})
+				}
+			}
+		}
+		return out, nil
+	}
+	return nil, fmt.Errorf("type %s not found in %s", r.Type, r.dir())
+}
+
+type qualifier struct {
+	local   string // package name of the struct
+	qualify bool   // prefix local types with local.
+	imports map[string]string
+	used    map[string]bool
+}
+
+func (q qualifier) expr(e ast.Expr) string {
+	switch t := e.(type) {
+	case *ast.Ident:
+		if !q.qualify || types.Universe.Lookup(t.Name) != nil {
+			return t.Name
+		}
+		return q.local + "." + t.Name
+	case *ast.SelectorExpr:
+		if x, ok := t.X.(*ast.Ident); ok {
+			if path, ok := q.imports[x.Name]; ok {
+				q.used[path] = true
+			}
+		}
+		return types.ExprString(t)
+	case *ast.StarExpr:
+		return "*" + q.expr(t.X)
+	case *ast.ArrayType:
+		if t.Len != nil {
+			return "[" + types.ExprString(t.Len) + "]" + q.expr(t.Elt)
+		}
+		return "[]" + q.expr(t.Elt)
+	case *ast.MapType:
+		return "map[" + q.expr(t.Key) + "]" + q.expr(t.Value)
+	}
+	return types.ExprString(e)
+}
+
+// match finds the source field for target name: exact, then ignoring case
+// (UserId -> UserID).
+func match(src []field, name string) (field, bool) {
+	for _, s := range src {
+		if s.name == name {
+			return s, true
+		}
+	}
+	for _, s := range src {
+		if strings.EqualFold(s.name, name) {
+			return s, true
+		}
+	}
+	return field{}, false
+}
+
+// pgWrappers are the pgtype structs with one value field and Valid.
+var pgWrappers = map[string][2]string{
+	"pgtype.Text":        {"String", "string"},
+	"pgtype.Int2":        {"Int16", "int16"},
+	"pgtype.Int4":        {"Int32", "int32"},
+	"pgtype.Int8":        {"Int64", "int64"},
+	"pgtype.Float4":      {"Float32", "float32"},
+	"pgtype.Float8":      {"Float64", "float64"},
+	"pgtype.Bool":        {"Bool", "bool"},
+	"pgtype.Timestamptz": {"Time", "time.Time"},
+	"pgtype.Timestamp":   {"Time", "time.Time"},
+	"pgtype.Date":        {"Time", "time.Time"},
+}
+
+const pgtypeImport = "github.com/jackc/pgx/v5/pgtype"
+
+// convert returns the expression turning v (of type from) into to, when
+// the types are compatible and every value of from survives the
+// conversion.
+func convert(v, from, to string, imports map[string]bool) (string, bool) {
+	switch {
+	case from == to:
+		return v, true
+	case widens(from, to),
+		from == "string" && to == "[]byte",
+		from == "[]byte" && to == "string":
+		return to + "(" + v + ")", true
+	case to == "*"+from: // v is a field of the by-value input, so its address is safe to keep
+		return "&" + v, true
+	}
+	if w, ok := pgWrappers[to]; ok {
+		if inner, ok := convert(v, from, w[1], imports); ok {
+			imports[pgtypeImport] = true
+			return fmt.Sprintf("%s{%s: %s, Valid: true}", to, w[0], inner), true
+		}
+	}
+	if w, ok := pgWrappers[from]; ok {
+		return convert(v+"."+w[0], w[1], to, imports)
+	}
+	return "", false
+}
+
+// numerics are the kind (i, u or f) and size of the numeric types; int
+// and uint count as 64 bits, as on the platforms services run on.
+var numerics = map[string]struct {
+	kind byte
+	bits int
+}{
+	"int": {'i', 64}, "int8": {'i', 8}, "int16": {'i', 16}, "int32": {'i', 32}, "int64": {'i', 64},
+	"uint": {'u', 64}, "uint8": {'u', 8}, "uint16": {'u', 16}, "uint32": {'u', 32}, "uint64": {'u', 64},
+	"float32": {'f', 24}, "float64": {'f', 53}, // bits of mantissa
+}
+
+func isNumeric(t string) bool {
+	_, ok := numerics[t]
+	return ok
+}
+
+// widens reports whether every value of numeric type from is exactly
+// representable in to, e.g. int32 -> int64, uint16 -> int32, int32 ->
+// float64, but not int64 -> int32, int -> uint or float64 -> int.
+func widens(from, to string) bool {
+	f, ok := numerics[from]
+	t, ok2 := numerics[to]
+	if !ok || !ok2 {
+		return false
+	}
+	switch {
+	case f.kind == t.kind:
+		return f.bits <= t.bits
+	case f.kind == 'u' && t.kind == 'i':
+		return f.bits < t.bits
+	case f.kind != 'f' && t.kind == 'f':
+		return f.bits <= t.bits // int32 fits float64's 53 bits, int64 does not
+	}
+	return false
+}
+
+// writeFunc appends the function to mapper.go unless it is already there.
+func writeFunc(path, pkg string, data tmpl.Mapper, imports map[string]bool) error {
+	src := "package " + pkg + "\n"
+	if raw, err := util.ReadFile(path); err == nil {
+		src = string(raw)
+	}
+	if strings.Contains(src, "func "+data.Func+"(") {
+		fmt.Printf("ℹ️  %s already exists in %s, leaving it alone\n", data.Func, path)
+		return nil
+	}
+	code, err := tmpl.Render("mapper_func.tmpl", data)
+	if err != nil {
+		return err
+	}
+	src += code
+	for imp := range imports {
+		src = util.InsertImport(src, `"`+imp+`"`)
+	}
+	return util.WriteGoFile(path, src)
+}
+
+// insertCall puts `<var> := <Func>(req)` in front of the method's
+// "// TODO: implement" (or at the top of its body).
+func insertCall(pkg, method string, data tmpl.Mapper, toType string) error {
+	path := filepath.Join(paths.RootUsecaseDir, pkg, "usecase.go")
+	raw, err := util.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	src := string(raw)
+
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, path, src, parser.ParseComments)
+	if err != nil {
+		return fmt.Errorf("parse %s: %w", path, err)
+	}
+	var fd *ast.FuncDecl
+	for _, d := range f.Decls {
+		if fn, ok := d.(*ast.FuncDecl); ok && fn.Recv != nil && fn.Name.Name == method && fn.Body != nil {
+			fd = fn
+		}
+	}
+	if fd == nil {
+		return fmt.Errorf("usecase method %s.%s not found in %s", pkg, method, path)
+	}
+	arg := ""
+	for _, p := range fd.Type.Params.List {
+		if types.ExprString(p.Type) == data.From && len(p.Names) == 1 {
+			arg = p.Names[0].Name
+		}
+	}
+	if arg == "" {
+		return fmt.Errorf("%s.%s takes no %s", pkg, method, data.From)
+	}
+
+	start, end := fset.Position(fd.Body.Lbrace).Offset, fset.Position(fd.Body.Rbrace).Offset
+	body := src[start:end]
+	if strings.Contains(body, data.Func+"(") {
+		fmt.Printf("ℹ️  %s already calls %s\n", method, data.Func)
+		return nil
+	}
+
+	v := strings.ToLower(toType[:1]) + toType[1:]
+	call := fmt.Sprintf("%s := %s(%s)\n\t_ = %s // TODO: use", v, data.Func, arg, v)
+	if i := strings.Index(body, "// TODO: implement"); i != -1 {
+		at := start + i
+		return util.WriteGoFile(path, src[:at]+call+"\n\t"+src[at:])
+	}
+	return util.WriteGoFile(path, src[:start+1]+"\n\t"+call+src[start+1:])
+}
@@ -0,0 +1,49 @@
+package mapper
+
+import "testing"
+
+func TestConvert(t *testing.T) {
+	tests := []struct {
+		from, to string
+		want     string // "" = left as a TODO
+	}{
+		{"string", "string", "in.X"},
+		{"int32", "int64", "int64(in.X)"},
+		{"int", "int64", "int64(in.X)"},
+		{"int64", "int", "int(in.X)"}, // int counts as 64 bits
+		{"uint16", "int32", "int32(in.X)"},
+		{"uint32", "uint", "uint(in.X)"},
+		{"int32", "float64", "float64(in.X)"},
+		{"int16", "float32", "float32(in.X)"},
+		{"float32", "float64", "float64(in.X)"},
+		{"string", "[]byte", "[]byte(in.X)"},
+		{"[]byte", "string", "string(in.X)"},
+		{"string", "*string", "&in.X"},
+		{"string", "pgtype.Text", "pgtype.Text{String: in.X, Valid: true}"},
+		{"int16", "pgtype.Int4", "pgtype.Int4{Int32: int32(in.X), Valid: true}"},
+		{"pgtype.Int4", "int64", "int64(in.X.Int32)"},
+
+		// lossy: narrowing, sign changes, float -> int
+		{"int64", "int32", ""},
+		{"int", "uint", ""},
+		{"uint64", "int64", ""},
+		{"int8", "uint64", ""},
+		{"float64", "int", ""},
+		{"float64", "float32", ""},
+		{"int64", "float64", ""},
+		{"int32", "float32", ""},
+		{"int64", "pgtype.Int4", ""},
+		{"pgtype.Int8", "int32", ""},
+		{"string", "int", ""},
+	}
+	for _, tt := range tests {
+		imports := map[string]bool{}
+		got, ok := convert("in.X", tt.from, tt.to, imports)
+		if !ok {
+			got = ""
+		}
+		if got != tt.want {
+			t.Errorf("convert(%s -> %s) = %q, %v; want %q", tt.from, tt.to, got, ok, tt.want)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+// Package migration writes timestamped SQL migrations into
+// paths.MigrationsDir, in golang-migrate or goose layout.
+package migration
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/AndreeJait/ntaps/internal/paths"
+	"github.com/AndreeJait/ntaps/internal/tmpl"
+	"github.com/AndreeJait/ntaps/internal/util"
+)
+
+// Formats lists the supported layouts.
+var Formats = []string{"migrate", "goose"}
+
+var nameRe = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// now is the clock versions are taken from.
+var now = time.Now
+
+// Create writes migration name in format and returns the created files.
+// Empty up/down render as TODO comments.
+func Create(name, format, up, down string) ([]string, error) {
+	if !nameRe.MatchString(name) {
+		return nil, fmt.Errorf("migration name %q must be snake_case (e.g. add_status_to_users)", name)
+	}
+	if existing := find(name); existing != "" {
+		return nil, fmt.Errorf("migration %s already exists: %s", name, existing)
+	}
+
+	data := tmpl.Migration{Name: name, Up: up, Down: down}
+	base := filepath.Join(paths.MigrationsDir, version()+"_"+name)
+
+	var files map[string]string
+	switch format {
+	case "migrate":
+		files = map[string]string{base + ".up.sql": "migration_up.tmpl", base + ".down.sql": "migration_down.tmpl"}
+	case "goose":
+		files = map[string]string{base + ".sql": "migration_goose.tmpl"}
+	default:
+		return nil, fmt.Errorf("unknown migration format %q (use %s)", format, strings.Join(Formats, "|"))
+	}
+
+	var out []string
+	for path, name := range files {
+		body, err := tmpl.Render(name, data)
+		if err != nil {
+			return nil, err
+		}
+		if err := util.WriteFile(path, body); err != nil {
+			return nil, err
+		}
+		out = append(out, path)
+	}
+	sort.Strings(out)
+	return out, nil
+}
+
+// CreateTable writes a create_<table>_table migration with a CREATE TABLE
+// skeleton. It returns no files when that migration already exists.
+func CreateTable(table, format string) ([]string, error) {
+	name := "create_" + table + "_table"
+	if existing := find(name); existing != "" {
+		fmt.Printf("ℹ️  %s exists, not creating another %s migration\n", existing, name)
+		return nil, nil
+	}
+	up, err := tmpl.Render("migration_create_table.tmpl", tmpl.Table{Table: table})
+	if err != nil {
+		return nil, err
+	}
+	return Create(name, format, up, "DROP TABLE IF EXISTS "+table+";\n")
+}
+
+// TableName is the table a repository package maps to: user -> users.
+func TableName(pkg string) string {
+	return util.Plural(strings.ToLower(pkg))
+}
+
+// version is a UTC timestamp (both tools accept 20060102150405), bumped
+// past any existing migration so versions stay unique and increasing.
+func version() string {
+	v := now().UTC().Format("20060102150405")
+	for _, f := range list() {
+		if fv := versionOf(f); fv >= v {
+			t, err := time.Parse("20060102150405", fv)
+			if err != nil {
+				continue
+			}
+			v = t.Add(time.Second).Format("20060102150405")
+		}
+	}
+	return v
+}
+
+func list() []string {
+	files, _ := util.Glob(filepath.Join(paths.MigrationsDir, "*.sql"))
+	return files
+}
+
+func versionOf(file string) string {
+	v, _, _ := strings.Cut(filepath.Base(file), "_")
+	return v
+}
+
+// find returns an existing migration file called name, if any.
+func find(name string) string {
+	for _, f := range list() {
+		base := strings.TrimSuffix(strings.TrimSuffix(strings.TrimSuffix(filepath.Base(f), ".sql"), ".up"), ".down")
+		if _, n, ok := strings.Cut(base, "_"); ok && n == name {
+			return f
+		}
+	}
+	return ""
+}
@@ -0,0 +1,390 @@
+package migration
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/AndreeJait/ntaps/internal/paths"
+	"github.com/AndreeJait/ntaps/internal/util"
+)
+
+// Table is a table as the migrations leave it.
+type Table struct {
+	Name    string
+	Columns []*Column
+}
+
+// Column is one column; Type is the SQL type, lowercased, without
+// length/precision and array suffixes (e.g. "character varying").
+type Column struct {
+	Name       string
+	Type       string
+	Array      bool
+	NotNull    bool
+	HasDefault bool // DEFAULT, serial, identity or GENERATED: left out of insert params
+}
+
+// LoadTable replays the CREATE/ALTER/DROP TABLE statements of every up
+// migration in paths.MigrationsDir, in version order, and returns table.
+// No database is needed; statements it does not understand are skipped.
+func LoadTable(table string) (*Table, error) {
+	files := list()
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no migrations in %s", paths.MigrationsDir)
+	}
+	sort.SliceStable(files, func(i, j int) bool { return versionLess(versionOf(files[i]), versionOf(files[j])) })
+
+	s := schema{}
+	for _, f := range files {
+		if strings.HasSuffix(f, ".down.sql") {
+			continue
+		}
+		raw, err := util.ReadFile(f)
+		if err != nil {
+			return nil, err
+		}
+		for _, stmt := range splitStatements(upSection(string(raw))) {
+			s.apply(stmt)
+		}
+	}
+
+	t, ok := s[unqualify(strings.ToLower(table))]
+	if !ok {
+		var names []string
+		for n := range s {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		return nil, fmt.Errorf("table %s not found in %s (have: %s)", table, paths.MigrationsDir, strings.Join(names, ", "))
+	}
+	return t, nil
+}
+
+// versionLess orders numeric versions of any width (1_x before 10_x).
+func versionLess(a, b string) bool {
+	a, b = strings.TrimLeft(a, "0"), strings.TrimLeft(b, "0")
+	if len(a) != len(b) {
+		return len(a) < len(b)
+	}
+	return a < b
+}
+
+// upSection drops a goose file's -- +goose Down part.
+func upSection(src string) string {
+	if i := gooseDownRe.FindStringIndex(src); i != nil {
+		return src[:i[0]]
+	}
+	return src
+}
+
+// splitStatements strips comments and splits src on top-level semicolons,
+// respecting quotes and $$ bodies. Whitespace is collapsed.
+func splitStatements(src string) []string {
+	var out []string
+	var b strings.Builder
+	flush := func() {
+		if s := strings.Join(strings.Fields(b.String()), " "); s != "" {
+			out = append(out, s)
+		}
+		b.Reset()
+	}
+	for i := 0; i < len(src); i++ {
+		c := src[i]
+		switch {
+		case c == '-' && strings.HasPrefix(src[i:], "--"):
+			for i < len(src) && src[i] != '\n' {
+				i++
+			}
+			b.WriteByte(' ')
+		case c == '/' && strings.HasPrefix(src[i:], "/*"):
+			end := strings.Index(src[i+2:], "*/")
+			if end < 0 {
+				i = len(src)
+			} else {
+				i += end + 3
+			}
+			b.WriteByte(' ')
+		case c == '\'' || c == '"':
+			end := i + 1
+			for end < len(src) && src[end] != c {
+				end++
+			}
+			b.WriteString(src[i:min(end+1, len(src))])
+			i = end
+		case c == '$':
+			tag := dollarTagRe.FindString(src[i:])
+			if tag == "" {
+				b.WriteByte(c)
+				continue
+			}
+			end := strings.Index(src[i+len(tag):], tag)
+			if end < 0 {
+				end = len(src) - i - len(tag)
+			}
+			b.WriteString(src[i:min(i+len(tag)+end+len(tag), len(src))])
+			i += len(tag) + end + len(tag) - 1
+		case c == ';':
+			flush()
+		default:
+			b.WriteByte(c)
+		}
+	}
+	flush()
+	return out
+}
+
+// splitTop splits s on commas outside parentheses and quotes.
+func splitTop(s string) []string {
+	var out []string
+	depth, start := 0, 0
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+		case c == ',' && depth == 0:
+			out = append(out, strings.TrimSpace(s[start:i]))
+			start = i + 1
+		}
+	}
+	if last := strings.TrimSpace(s[start:]); last != "" {
+		out = append(out, last)
+	}
+	return out
+}
+
+// closing returns the index of the parenthesis closing s[open].
+func closing(s string, open int) int {
+	depth := 0
+	var quote byte
+	for i := open; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+const ident = `("[^"]+"|[\w$]+)`
+const qualified = `((?:` + ident + `\.)?` + ident + `)`
+
+var (
+	createTableRe = regexp.MustCompile(`(?i)^CREATE\s+(?:(?:GLOBAL|LOCAL)\s+)?(?:(?:TEMP|TEMPORARY|UNLOGGED)\s+)?TABLE\s+(?:IF\s+NOT\s+EXISTS\s+)?` + qualified + `\s*\(`)
+	alterTableRe  = regexp.MustCompile(`(?i)^ALTER\s+TABLE\s+(?:IF\s+EXISTS\s+)?(?:ONLY\s+)?` + qualified + `\s+(.*)$`)
+	dropTableRe   = regexp.MustCompile(`(?i)^DROP\s+TABLE\s+(?:IF\s+EXISTS\s+)?(.*?)(?:\s+(?:CASCADE|RESTRICT))?$`)
+
+	renameTableRe  = regexp.MustCompile(`(?i)^RENAME\s+TO\s+` + ident + `$`)
+	renameColRe    = regexp.MustCompile(`(?i)^RENAME\s+(?:COLUMN\s+)?` + ident + `\s+TO\s+` + ident + `$`)
+	addColRe       = regexp.MustCompile(`(?i)^ADD\s+(?:COLUMN\s+)?(?:IF\s+NOT\s+EXISTS\s+)?(.*)$`)
+	dropColRe      = regexp.MustCompile(`(?i)^DROP\s+(?:COLUMN\s+)?(?:IF\s+EXISTS\s+)?` + ident + `(?:\s+(?:CASCADE|RESTRICT))?$`)
+	alterColRe     = regexp.MustCompile(`(?i)^ALTER\s+(?:COLUMN\s+)?` + ident + `\s+(.*)$`)
+	setTypeRe      = regexp.MustCompile(`(?i)^(?:SET\s+DATA\s+)?TYPE\s+(.*?)(?:\s+(?:USING|COLLATE)\s+.*)?$`)
+	constraintRe   = regexp.MustCompile(`(?i)^(?:CONSTRAINT\s+\S+\s+)?(PRIMARY\s+KEY|UNIQUE|CHECK|FOREIGN\s+KEY|EXCLUDE)\b(.*)$`)
+	primaryColsRe  = regexp.MustCompile(`^\s*\(([^)]*)\)`)
+	typeEndRe      = regexp.MustCompile(`(?i)\s(CONSTRAINT|NOT|NULL|DEFAULT|PRIMARY|UNIQUE|REFERENCES|CHECK|GENERATED|COLLATE)\b`)
+	notNullRe      = regexp.MustCompile(`(?i)\bNOT\s+NULL\b|\bPRIMARY\s+KEY\b`)
+	defaultRe      = regexp.MustCompile(`(?i)\bDEFAULT\b|\bGENERATED\b`)
+	typeModsRe     = regexp.MustCompile(`\s*\([^)]*\)`)
+	arraySuffixRe  = regexp.MustCompile(`(?i)(\s*\[\s*\d*\s*\])+$|\s+ARRAY(\s*\[\s*\d*\s*\])?$`)
+	reservedColRe  = regexp.MustCompile(`(?i)^(CONSTRAINT|LIKE)\b`)
+	renameOtherRe  = regexp.MustCompile(`(?i)^RENAME\s+CONSTRAINT\b`)
+	dropOtherRe    = regexp.MustCompile(`(?i)^DROP\s+CONSTRAINT\b`)
+	addOtherRe     = regexp.MustCompile(`(?i)^ADD\s+CONSTRAINT\b`)
+	columnRe       = regexp.MustCompile(`^` + ident + `\s+(.*)$`)
+	gooseDownRe    = regexp.MustCompile(`(?im)^\s*--\s*\+goose\s+down`)
+	dollarTagRe    = regexp.MustCompile(`^\$\w*\$`)
+	serialTypeName = map[string]bool{"serial": true, "bigserial": true, "smallserial": true, "serial2": true, "serial4": true, "serial8": true}
+)
+
+type schema map[string]*Table
+
+func (s schema) apply(stmt string) {
+	switch {
+	case createTableRe.MatchString(stmt):
+		m := createTableRe.FindStringSubmatchIndex(stmt)
+		n := unqualify(unquote(stmt[m[2]:m[3]]))
+		end := closing(stmt, m[1]-1)
+		if end < 0 {
+			return
+		}
+		t := &Table{Name: n}
+		for _, item := range splitTop(stmt[m[1]:end]) {
+			t.addItem(item)
+		}
+		s[n] = t
+	case alterTableRe.MatchString(stmt):
+		m := alterTableRe.FindStringSubmatch(stmt)
+		t, ok := s[unqualify(unquote(m[1]))]
+		if !ok {
+			return
+		}
+		for _, action := range splitTop(m[len(m)-1]) {
+			if r := renameTableRe.FindStringSubmatch(action); r != nil {
+				delete(s, t.Name)
+				t.Name = unquote(r[1])
+				s[t.Name] = t
+				continue
+			}
+			t.alter(action)
+		}
+	case dropTableRe.MatchString(stmt):
+		for _, n := range splitTop(dropTableRe.FindStringSubmatch(stmt)[1]) {
+			delete(s, unqualify(unquote(n)))
+		}
+	}
+}
+
+// addItem adds a column definition or applies a table constraint.
+func (t *Table) addItem(item string) {
+	if m := constraintRe.FindStringSubmatch(item); m != nil {
+		if strings.EqualFold(strings.Join(strings.Fields(m[1]), " "), "PRIMARY KEY") {
+			if cols := primaryColsRe.FindStringSubmatch(m[2]); cols != nil {
+				for _, c := range splitTop(cols[1]) {
+					if col := t.column(unquote(c)); col != nil {
+						col.NotNull = true
+					}
+				}
+			}
+		}
+		return
+	}
+	if reservedColRe.MatchString(item) {
+		return
+	}
+	if c := parseColumn(item); c != nil {
+		t.Columns = append(t.Columns, c)
+	}
+}
+
+func (t *Table) alter(action string) {
+	switch {
+	case renameOtherRe.MatchString(action), dropOtherRe.MatchString(action):
+	case addOtherRe.MatchString(action):
+		t.addItem(strings.TrimSpace(action[3:]))
+	case renameColRe.MatchString(action):
+		m := renameColRe.FindStringSubmatch(action)
+		if c := t.column(unquote(m[1])); c != nil {
+			c.Name = unquote(m[2])
+		}
+	case addColRe.MatchString(action):
+		def := addColRe.FindStringSubmatch(action)[1]
+		if constraintRe.MatchString(def) {
+			t.addItem(def)
+			return
+		}
+		if c := parseColumn(def); c != nil && t.column(c.Name) == nil {
+			t.Columns = append(t.Columns, c)
+		}
+	case dropColRe.MatchString(action):
+		n := unquote(dropColRe.FindStringSubmatch(action)[1])
+		for i, c := range t.Columns {
+			if c.Name == n {
+				t.Columns = append(t.Columns[:i], t.Columns[i+1:]...)
+				break
+			}
+		}
+	case alterColRe.MatchString(action):
+		m := alterColRe.FindStringSubmatch(action)
+		c := t.column(unquote(m[1]))
+		if c == nil {
+			return
+		}
+		change := strings.ToUpper(m[2])
+		switch {
+		case setTypeRe.MatchString(m[2]):
+			c.Type, c.Array = sqlType(setTypeRe.FindStringSubmatch(m[2])[1])
+		case change == "SET NOT NULL":
+			c.NotNull = true
+		case change == "DROP NOT NULL":
+			c.NotNull = false
+		case strings.HasPrefix(change, "SET DEFAULT"), strings.HasPrefix(change, "ADD GENERATED"):
+			c.HasDefault = true
+		case change == "DROP DEFAULT", strings.HasPrefix(change, "DROP IDENTITY"):
+			c.HasDefault = false
+		}
+	}
+}
+
+func (t *Table) column(n string) *Column {
+	for _, c := range t.Columns {
+		if c.Name == n {
+			return c
+		}
+	}
+	return nil
+}
+
+// parseColumn parses `name type [constraints...]`.
+func parseColumn(def string) *Column {
+	m := columnRe.FindStringSubmatch(def)
+	if m == nil {
+		return nil
+	}
+	rest := m[2]
+	typ, mods := rest, ""
+	if i := typeEndRe.FindStringIndex(" " + rest); i != nil {
+		typ, mods = rest[:max(i[0]-1, 0)], rest[max(i[0]-1, 0):]
+	}
+	c := &Column{Name: unquote(m[1])}
+	c.Type, c.Array = sqlType(typ)
+	c.NotNull = notNullRe.MatchString(mods)
+	c.HasDefault = defaultRe.MatchString(mods)
+	if serialTypeName[c.Type] {
+		c.NotNull, c.HasDefault = true, true
+	}
+	return c
+}
+
+// sqlType normalizes "VARCHAR(20)[]" to ("varchar", true); aliases such
+// as varchar/character varying are left to the caller.
+func sqlType(s string) (string, bool) {
+	s = strings.TrimSpace(s)
+	array := arraySuffixRe.MatchString(s)
+	s = arraySuffixRe.ReplaceAllString(s, "")
+	s = typeModsRe.ReplaceAllString(s, "")
+	s = strings.ToLower(strings.Join(strings.Fields(strings.ReplaceAll(s, `"`, "")), " "))
+	s = strings.TrimPrefix(s, "pg_catalog.")
+	return s, array
+}
+
+// unquote unquotes an identifier; unquoted ones fold to lower case.
+func unquote(s string) string {
+	var parts []string
+	for _, p := range strings.Split(strings.TrimSpace(s), ".") {
+		if strings.HasPrefix(p, `"`) {
+			parts = append(parts, strings.Trim(p, `"`))
+		} else {
+			parts = append(parts, strings.ToLower(p))
+		}
+	}
+	return strings.Join(parts, ".")
+}
+
+// unqualify drops a schema prefix (public.users -> users).
+func unqualify(s string) string {
+	return s[strings.LastIndex(s, ".")+1:]
+}
@@ -0,0 +1,190 @@
+package migration
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// replay applies src's up section the way LoadTable does and describes
+// table as "name type[] notnull default" per column.
+func replay(src, table string) []string {
+	s := schema{}
+	for _, stmt := range splitStatements(upSection(src)) {
+		s.apply(stmt)
+	}
+	t, ok := s[table]
+	if !ok {
+		return nil
+	}
+	var out []string
+	for _, c := range t.Columns {
+		d := c.Name + " " + c.Type
+		if c.Array {
+			d += "[]"
+		}
+		if c.NotNull {
+			d += " notnull"
+		}
+		if c.HasDefault {
+			d += " default"
+		}
+		out = append(out, d)
+	}
+	return out
+}
+
+func TestReplay(t *testing.T) {
+	const create = `
+CREATE TABLE IF NOT EXISTS public.users (
+	id BIGSERIAL PRIMARY KEY,
+	email VARCHAR(255) NOT NULL UNIQUE,
+	name text,
+	tags text[],
+	created_at timestamptz NOT NULL DEFAULT now(),
+	CONSTRAINT users_email_check CHECK (email <> '')
+);
+`
+	tests := []struct {
+		name  string
+		src   string
+		table string
+		want  []string
+	}{
+		{
+			name:  "create",
+			src:   create,
+			table: "users",
+			want: []string{
+				"id bigserial notnull default",
+				"email varchar notnull",
+				"name text",
+				"tags text[]",
+				"created_at timestamptz notnull default",
+			},
+		},
+		{
+			name: "table primary key and quoted names",
+			src: `CREATE TABLE "Orders" ("ID" int, "userId" int, PRIMARY KEY ("ID", "userId"));
+			-- a comment; with a semicolon
+			/* and a block; comment */`,
+			table: "Orders",
+			want:  []string{"ID int notnull", "userId int notnull"},
+		},
+		{
+			name: "add, drop and rename columns",
+			src: create + `
+ALTER TABLE users ADD COLUMN age int, DROP COLUMN tags;
+ALTER TABLE users RENAME COLUMN name TO full_name;
+ALTER TABLE ONLY users ADD IF NOT EXISTS email text;`,
+			table: "users",
+			want: []string{
+				"id bigserial notnull default",
+				"email varchar notnull",
+				"full_name text",
+				"created_at timestamptz notnull default",
+				"age int",
+			},
+		},
+		{
+			name: "alter column",
+			src: create + `
+ALTER TABLE users ALTER COLUMN name SET NOT NULL;
+ALTER TABLE users ALTER email TYPE citext USING email::citext;
+ALTER TABLE users ALTER COLUMN created_at DROP DEFAULT;
+ALTER TABLE users ALTER COLUMN email DROP NOT NULL;`,
+			table: "users",
+			want: []string{
+				"id bigserial notnull default",
+				"email citext",
+				"name text notnull",
+				"tags text[]",
+				"created_at timestamptz notnull",
+			},
+		},
+		{
+			name:  "rename table",
+			src:   create + `ALTER TABLE users RENAME TO members;`,
+			table: "members",
+			want: []string{
+				"id bigserial notnull default",
+				"email varchar notnull",
+				"name text",
+				"tags text[]",
+				"created_at timestamptz notnull default",
+			},
+		},
+		{
+			name:  "drop table",
+			src:   create + `DROP TABLE IF EXISTS users CASCADE;`,
+			table: "users",
+			want:  nil,
+		},
+		{
+			name: "goose down section is ignored",
+			src: "-- +goose Up\n" + create + `
+-- +goose Down
+DROP TABLE users;`,
+			table: "users",
+			want: []string{
+				"id bigserial notnull default",
+				"email varchar notnull",
+				"name text",
+				"tags text[]",
+				"created_at timestamptz notnull default",
+			},
+		},
+		{
+			name: "function bodies do not split statements",
+			src: `CREATE TABLE t (id int);
+CREATE FUNCTION f() RETURNS trigger AS $$ BEGIN DROP TABLE t; END; $$ LANGUAGE plpgsql;`,
+			table: "t",
+			want:  []string{"id int"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := replay(tt.src, tt.table); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("columns:\n got  %s\n want %s", strings.Join(got, ", "), strings.Join(tt.want, ", "))
+			}
+		})
+	}
+}
+
+func TestSQLType(t *testing.T) {
+	tests := []struct {
+		in    string
+		typ   string
+		array bool
+	}{
+		{"VARCHAR(20)", "varchar", false},
+		{"character varying(20)[]", "character varying", true},
+		{"numeric(10, 2)", "numeric", false},
+		{"int ARRAY", "int", true},
+		{`pg_catalog."timestamp"`, "timestamp", false},
+		{"double precision", "double precision", false},
+	}
+	for _, tt := range tests {
+		typ, array := sqlType(tt.in)
+		if typ != tt.typ || array != tt.array {
+			t.Errorf("sqlType(%q) = %q, %v; want %q, %v", tt.in, typ, array, tt.typ, tt.array)
+		}
+	}
+}
+
+func TestVersionLess(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"1", "10", true},
+		{"10", "9", false},
+		{"0002", "10", true},
+		{"20250101120000", "20250101120001", true},
+	}
+	for _, tt := range tests {
+		if got := versionLess(tt.a, tt.b); got != tt.want {
+			t.Errorf("versionLess(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
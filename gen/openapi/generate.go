@@ -0,0 +1,299 @@
+package openapi
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/AndreeJait/ntaps/gen/handler"
+	"github.com/AndreeJait/ntaps/gen/usecase"
+	"github.com/AndreeJait/ntaps/internal/paths"
+	"github.com/AndreeJait/ntaps/internal/util"
+)
+
+var (
+	pathParamRe = regexp.MustCompile(`\{([^}]+)\}`)
+	prefixSegRe = regexp.MustCompile(`^(api|v\d+)$`)
+	nonIdentRe  = regexp.MustCompile(`[^a-z0-9]`)
+)
+
+// route is one operation mapped onto handler.Run's arguments.
+type route struct {
+	pkg          string
+	endpointType string
+	endpoint     string
+	verb         string
+	ucMethod     string
+	method       string
+	tag          string
+	op           *Operation
+	params       []*Parameter
+	note         string // why the route is not served at the spec's path
+}
+
+// Generate creates/extends a route, usecase method and DTOs per operation
+// and returns how many operations it generated. Every step is additive, so
+// re-running after the spec changed only adds new operations and fields.
+func Generate(d *Document) (int, error) {
+	routes, err := d.routes()
+	if err != nil {
+		return 0, err
+	}
+	for _, r := range routes {
+		fmt.Printf("• route %s %s → %s.%s\n", r.verb, util.RouterPath(r.pkg, r.endpointType, r.endpoint), r.pkg, r.ucMethod)
+		if r.note != "" {
+			fmt.Println("ℹ️ ", r.note)
+		}
+		if err := d.generate(r); err != nil {
+			return 0, fmt.Errorf("%s %s: %w", r.verb, r.endpoint, err)
+		}
+	}
+	return len(routes), nil
+}
+
+func (d *Document) generate(r route) error {
+	dtoPath := filepath.Join(paths.RootUsecaseDir, r.pkg, "dto.go")
+	t := &types{doc: d, path: dtoPath, pkg: r.pkg, done: map[string]bool{}}
+
+	var req []util.StructField
+	for _, p := range r.params {
+		tagKey := map[string]string{"path": "param", "query": "query", "header": "header", "cookie": "cookie"}[p.In]
+		if tagKey == "" {
+			continue
+		}
+		typ, err := t.goType(p.Schema, r.ucMethod+util.ExportedName(p.Name))
+		if err != nil {
+			return err
+		}
+		if tagKey == "cookie" && typ != "string" {
+			fmt.Printf("ℹ️  %s: cookie %s is %s, only string cookies are bound; read it by hand\n", r.ucMethod, p.Name, typ)
+			continue
+		}
+		tag := withRules(fmt.Sprintf(`%s:"%s"`, tagKey, p.Name), t.rules(p.Schema, p.Required || p.In == "path"))
+		req = append(req, util.StructField{Name: util.ExportedName(p.Name), Type: typ, Tag: tag})
+	}
+
+	body, err := d.requestBody(r.op.RequestBody)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		s := jsonSchema(body.Content)
+		if len(t.properties(s)) == 0 {
+			fmt.Printf("ℹ️  %s: request body is not a JSON object; add its fields to %sRequest by hand\n", r.ucMethod, r.ucMethod)
+		}
+		f, err := t.fields(s, r.ucMethod+"Request")
+		if err != nil {
+			return err
+		}
+		// fields follows t.properties(s) one to one
+		required := t.required(s)
+		for i, p := range t.properties(s) {
+			f[i].Tag = withRules(f[i].Tag, t.rules(p.Schema, required[p.Name]))
+		}
+		req = append(req, f...)
+	}
+
+	respSchema, err := d.successSchema(r.op)
+	if err != nil {
+		return err
+	}
+
+	withParam, withResp := len(req) > 0 || body != nil, respSchema != nil
+
+	// usecase + DTO fields first, so the handler sees typed path params
+	if err := usecase.Run(r.pkg, r.ucMethod, withParam, withResp); err != nil {
+		return err
+	}
+	if withParam {
+		if err := usecase.EnsureDTOType(dtoPath, r.pkg, r.ucMethod+"Request", req); err != nil {
+			return err
+		}
+	}
+	if withResp {
+		var resp []util.StructField
+		if len(t.properties(respSchema)) > 0 {
+			if resp, err = t.fields(respSchema, r.ucMethod+"Response"); err != nil {
+				return err
+			}
+		} else {
+			typ, err := t.goType(respSchema, r.ucMethod+"Data")
+			if err != nil {
+				return err
+			}
+			resp = []util.StructField{{Name: "Items", Type: typ, Tag: `json:"items"`}}
+			if respSchema.Type != "array" {
+				resp = []util.StructField{{Name: "Data", Type: typ, Tag: `json:"data"`}}
+			}
+		}
+		if err := usecase.EnsureDTOType(dtoPath, r.pkg, r.ucMethod+"Response", resp); err != nil {
+			return err
+		}
+	}
+
+	return handler.Run(r.pkg, r.pkg, r.endpointType, r.endpoint, withParam, withResp, r.ucMethod, r.method, r.tag, r.verb, successStatus(r.op, withResp), nil, "")
+}
+
+// routes maps every operation, sorted by path then verb.
+func (d *Document) routes() ([]route, error) {
+	var keys []string
+	for k := range d.Paths {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var out []route
+	for _, p := range keys {
+		item := d.Paths[p]
+		ops := item.operations()
+		for _, verb := range handler.Verbs {
+			op, ok := ops[verb]
+			if !ok {
+				continue
+			}
+			r, err := d.route(p, verb, item, op)
+			if err != nil {
+				return nil, fmt.Errorf("%s %s: %w", verb, p, err)
+			}
+			out = append(out, r)
+		}
+	}
+	return out, nil
+}
+
+func (d *Document) route(path, verb string, item PathItem, op *Operation) (route, error) {
+	r := route{verb: verb, op: op}
+
+	// /internal/<pkg>/... is the internal group; otherwise the security
+	// scheme decides: any -> private, none -> public.
+	segs := strings.Split(strings.Trim(path, "/"), "/")
+	if segs[0] == "internal" {
+		r.endpointType = "internal"
+		segs = segs[1:]
+	} else if r.endpointType = d.endpointType(op); r.endpointType == "internal" {
+		return r, fmt.Errorf("basic auth routes are mounted at /internal/<pkg>; move the path there")
+	}
+
+	// the package is the first tag, else the first path segment after a
+	// version/api prefix; the handler's groups are mounted at /<pkg>, so
+	// the path from the segment naming it on becomes the endpoint
+	if len(op.Tags) > 0 {
+		r.pkg = nonIdentRe.ReplaceAllString(strings.ToLower(op.Tags[0]), "")
+		r.tag = op.Tags[0]
+	} else {
+		for len(segs) > 1 && prefixSegRe.MatchString(segs[0]) {
+			segs = segs[1:]
+		}
+		if !strings.HasPrefix(segs[0], "{") {
+			r.pkg = nonIdentRe.ReplaceAllString(strings.ToLower(segs[0]), "")
+		}
+	}
+	if r.pkg == "" || r.pkg[0] >= '0' && r.pkg[0] <= '9' {
+		return r, fmt.Errorf("cannot derive a package name; add a tag")
+	}
+	if r.tag == "" {
+		r.tag = util.ToPascalCase(r.pkg)
+	}
+
+	rest := segs
+	for i, seg := range segs {
+		if !strings.HasPrefix(seg, "{") && nonIdentRe.ReplaceAllString(strings.ToLower(seg), "") == r.pkg {
+			rest = segs[i+1:]
+			break
+		}
+	}
+	ep := ""
+	if len(rest) > 0 {
+		ep = "/" + strings.Join(rest, "/")
+		r.endpoint = pathParamRe.ReplaceAllString(ep, ":$1")
+	}
+	if served := util.RouterPath(r.pkg, r.endpointType, ep); served != path {
+		r.note = fmt.Sprintf("%s is served at %s (the /%s group plus the rest of the path); mount any prefix on the Echo group", path, served, r.pkg)
+	}
+
+	r.ucMethod = util.ExportedName(op.OperationID)
+	if r.ucMethod == "" {
+		r.ucMethod = util.ExportedName(strings.ToLower(verb) + " " + pathParamRe.ReplaceAllString(path, "by $1"))
+	}
+	if r.ucMethod[0] >= '0' && r.ucMethod[0] <= '9' {
+		return r, fmt.Errorf("operationId %q is not a valid Go identifier", op.OperationID)
+	}
+	r.method = strings.ToLower(r.ucMethod[:1]) + r.ucMethod[1:]
+
+	// path-level params, overridden by operation params with the same name+in
+	byKey := map[string]int{}
+	for _, raw := range append(append([]*Parameter{}, item.Parameters...), op.Parameters...) {
+		p, err := d.parameter(raw)
+		if err != nil {
+			return r, err
+		}
+		if i, ok := byKey[p.In+":"+p.Name]; ok {
+			r.params[i] = p
+			continue
+		}
+		byKey[p.In+":"+p.Name] = len(r.params)
+		r.params = append(r.params, p)
+	}
+	return r, nil
+}
+
+func (d *Document) endpointType(op *Operation) string {
+	reqs := d.Security
+	if op.Security != nil {
+		reqs = op.Security
+	}
+	if reqs == nil || len(*reqs) == 0 {
+		return "public"
+	}
+	for name := range (*reqs)[0] {
+		if s := d.Components.SecuritySchemes[name]; s != nil && s.Type == "http" && strings.EqualFold(s.Scheme, "basic") {
+			return "internal"
+		}
+	}
+	if len((*reqs)[0]) == 0 { // `security: [{}]` = optional auth
+		return "public"
+	}
+	return "private"
+}
+
+// successStatus is the lowest documented 2xx status, or 0 (the verb's
+// default) when there is none or it cannot carry the Response.
+func successStatus(op *Operation, withResp bool) int {
+	var codes []string
+	for c := range op.Responses {
+		if strings.HasPrefix(c, "2") {
+			codes = append(codes, c)
+		}
+	}
+	sort.Strings(codes)
+	for _, c := range codes {
+		if n, err := strconv.Atoi(c); err == nil && handler.CheckStatus(n, withResp) == nil {
+			return n
+		}
+	}
+	return 0
+}
+
+// successSchema is the JSON schema of the lowest 2xx response, if any.
+func (d *Document) successSchema(op *Operation) (*Schema, error) {
+	var codes []string
+	for c := range op.Responses {
+		if strings.HasPrefix(c, "2") {
+			codes = append(codes, c)
+		}
+	}
+	sort.Strings(codes)
+	for _, c := range codes {
+		resp, err := d.response(op.Responses[c])
+		if err != nil {
+			return nil, err
+		}
+		if s := jsonSchema(resp.Content); s != nil {
+			return s, nil
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,256 @@
+// Package openapi maps an OpenAPI 3 document onto the generators: every
+// operation becomes a handler route + usecase method, and request/response
+// schemas become DTO fields. Only what ntaps needs is decoded.
+package openapi
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/AndreeJait/ntaps/internal/util"
+)
+
+type Document struct {
+	OpenAPI    string              `yaml:"openapi"`
+	Paths      map[string]PathItem `yaml:"paths"`
+	Components Components          `yaml:"components"`
+	Security   *[]Requirement      `yaml:"security"`
+}
+
+// Requirement is one security requirement object: scheme name -> scopes.
+type Requirement map[string][]string
+
+type Components struct {
+	Schemas         map[string]*Schema         `yaml:"schemas"`
+	Parameters      map[string]*Parameter      `yaml:"parameters"`
+	RequestBodies   map[string]*RequestBody    `yaml:"requestBodies"`
+	Responses       map[string]*Response       `yaml:"responses"`
+	SecuritySchemes map[string]*SecurityScheme `yaml:"securitySchemes"`
+}
+
+type SecurityScheme struct {
+	Type   string `yaml:"type"`   // http, apiKey, oauth2, openIdConnect
+	Scheme string `yaml:"scheme"` // basic, bearer (type http)
+}
+
+type PathItem struct {
+	Parameters []*Parameter `yaml:"parameters"`
+	Get        *Operation   `yaml:"get"`
+	Put        *Operation   `yaml:"put"`
+	Post       *Operation   `yaml:"post"`
+	Delete     *Operation   `yaml:"delete"`
+	Patch      *Operation   `yaml:"patch"`
+	Head       *Operation   `yaml:"head"`
+	Options    *Operation   `yaml:"options"`
+}
+
+// operations returns the item's operations by upper-case verb.
+func (p PathItem) operations() map[string]*Operation {
+	out := map[string]*Operation{}
+	for verb, op := range map[string]*Operation{
+		"GET": p.Get, "PUT": p.Put, "POST": p.Post, "DELETE": p.Delete,
+		"PATCH": p.Patch, "HEAD": p.Head, "OPTIONS": p.Options,
+	} {
+		if op != nil {
+			out[verb] = op
+		}
+	}
+	return out
+}
+
+type Operation struct {
+	OperationID string               `yaml:"operationId"`
+	Tags        []string             `yaml:"tags"`
+	Summary     string               `yaml:"summary"`
+	Parameters  []*Parameter         `yaml:"parameters"`
+	RequestBody *RequestBody         `yaml:"requestBody"`
+	Responses   map[string]*Response `yaml:"responses"`
+	Security    *[]Requirement       `yaml:"security"` // nil = inherit the document's
+}
+
+type Parameter struct {
+	Ref      string  `yaml:"$ref"`
+	Name     string  `yaml:"name"`
+	In       string  `yaml:"in"` // path, query, header, cookie
+	Required bool    `yaml:"required"`
+	Schema   *Schema `yaml:"schema"`
+}
+
+type RequestBody struct {
+	Ref     string               `yaml:"$ref"`
+	Content map[string]MediaType `yaml:"content"`
+}
+
+type Response struct {
+	Ref     string               `yaml:"$ref"`
+	Content map[string]MediaType `yaml:"content"`
+}
+
+type MediaType struct {
+	Schema *Schema `yaml:"schema"`
+}
+
+type Schema struct {
+	Ref        string     `yaml:"$ref"`
+	Type       schemaType `yaml:"type"`
+	Format     string     `yaml:"format"`
+	Properties properties `yaml:"properties"`
+	Items      *Schema    `yaml:"items"`
+	AllOf      []*Schema  `yaml:"allOf"`
+	OneOf      []*Schema  `yaml:"oneOf"`
+	AnyOf      []*Schema  `yaml:"anyOf"`
+
+	// constraints mapped to validate tags on Request fields
+	Required  []string `yaml:"required"`
+	MinLength *int     `yaml:"minLength"`
+	MaxLength *int     `yaml:"maxLength"`
+	Minimum   *float64 `yaml:"minimum"`
+	Maximum   *float64 `yaml:"maximum"`
+	Enum      []string `yaml:"enum"`
+}
+
+// schemaType accepts both `type: string` and 3.1's `type: [string, "null"]`.
+type schemaType string
+
+func (t *schemaType) UnmarshalYAML(n *yaml.Node) error {
+	if n.Kind == yaml.ScalarNode {
+		*t = schemaType(n.Value)
+		return nil
+	}
+	var list []string
+	if err := n.Decode(&list); err != nil {
+		return err
+	}
+	for _, s := range list {
+		if s != "null" {
+			*t = schemaType(s)
+			break
+		}
+	}
+	return nil
+}
+
+// property is one schema property; properties keep the document's order so
+// generated struct fields read like the spec.
+type property struct {
+	Name   string
+	Schema *Schema
+}
+
+type properties []property
+
+func (p *properties) UnmarshalYAML(n *yaml.Node) error {
+	if n.Kind != yaml.MappingNode {
+		return fmt.Errorf("line %d: properties must be a mapping", n.Line)
+	}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		var s Schema
+		if err := n.Content[i+1].Decode(&s); err != nil {
+			return err
+		}
+		*p = append(*p, property{Name: n.Content[i].Value, Schema: &s})
+	}
+	return nil
+}
+
+// Load reads an OpenAPI 3 document (YAML or JSON).
+func Load(path string) (*Document, error) {
+	raw, err := util.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read spec %s: %w", path, err)
+	}
+	var d Document
+	if err := yaml.Unmarshal(raw, &d); err != nil {
+		return nil, fmt.Errorf("parse spec %s: %w", path, err)
+	}
+	if !strings.HasPrefix(d.OpenAPI, "3.") {
+		return nil, fmt.Errorf("%s: only OpenAPI 3.x is supported (openapi: %q)", path, d.OpenAPI)
+	}
+	return &d, nil
+}
+
+// refName returns X for "#/components/<kind>/X".
+func refName(ref, kind string) (string, error) {
+	prefix := "#/components/" + kind + "/"
+	if !strings.HasPrefix(ref, prefix) {
+		return "", fmt.Errorf("unsupported $ref %q (only local %s refs)", ref, prefix+"…")
+	}
+	return strings.TrimPrefix(ref, prefix), nil
+}
+
+// parameter, requestBody and response follow $ref chains through the
+// components; seen guards against refs that lead back to themselves.
+func (d *Document) parameter(p *Parameter) (*Parameter, error) {
+	seen := map[string]bool{}
+	for p.Ref != "" {
+		name, err := refName(p.Ref, "parameters")
+		if err != nil {
+			return nil, err
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("cyclic $ref %q", p.Ref)
+		}
+		seen[name] = true
+		r, ok := d.Components.Parameters[name]
+		if !ok || r == nil {
+			return nil, fmt.Errorf("$ref %q not found", p.Ref)
+		}
+		p = r
+	}
+	return p, nil
+}
+
+func (d *Document) requestBody(b *RequestBody) (*RequestBody, error) {
+	seen := map[string]bool{}
+	for b != nil && b.Ref != "" {
+		name, err := refName(b.Ref, "requestBodies")
+		if err != nil {
+			return nil, err
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("cyclic $ref %q", b.Ref)
+		}
+		seen[name] = true
+		r, ok := d.Components.RequestBodies[name]
+		if !ok || r == nil {
+			return nil, fmt.Errorf("$ref %q not found", b.Ref)
+		}
+		b = r
+	}
+	return b, nil
+}
+
+func (d *Document) response(r *Response) (*Response, error) {
+	seen := map[string]bool{}
+	for r != nil && r.Ref != "" {
+		name, err := refName(r.Ref, "responses")
+		if err != nil {
+			return nil, err
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("cyclic $ref %q", r.Ref)
+		}
+		seen[name] = true
+		x, ok := d.Components.Responses[name]
+		if !ok || x == nil {
+			return nil, fmt.Errorf("$ref %q not found", r.Ref)
+		}
+		r = x
+	}
+	return r, nil
+}
+
+// jsonSchema picks the application/json (or any +json) schema of a content map.
+func jsonSchema(content map[string]MediaType) *Schema {
+	if mt, ok := content["application/json"]; ok {
+		return mt.Schema
+	}
+	for ct, mt := range content {
+		if strings.HasSuffix(ct, "+json") {
+			return mt.Schema
+		}
+	}
+	return nil
+}
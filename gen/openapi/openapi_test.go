@@ -0,0 +1,314 @@
+package openapi
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+const testSpec = `
+openapi: 3.0.3
+security: [{bearer: []}]
+components:
+  securitySchemes:
+    bearer: {type: http, scheme: bearer}
+    basic: {type: http, scheme: basic}
+  parameters:
+    OrderID: {$ref: '#/components/parameters/OrderIDInner'}
+    OrderIDInner: {name: orderId, in: path, required: true, schema: {type: string}}
+    Limit: {name: limit, in: query, schema: {type: integer}}
+  responses:
+    Order:
+      content:
+        application/json:
+          schema: {$ref: '#/components/schemas/Order'}
+  schemas:
+    Order:
+      type: object
+      properties:
+        id: {type: string}
+paths:
+  /orders/{orderId}:
+    parameters:
+      - $ref: '#/components/parameters/OrderID'
+      - {name: verbose, in: query, schema: {type: boolean}}
+    get:
+      operationId: getOrder
+      tags: [Shop]
+      parameters:
+        - {name: verbose, in: query, required: true, schema: {type: string}}
+        - $ref: '#/components/parameters/Limit'
+      responses:
+        "200": {$ref: '#/components/responses/Order'}
+  /orders:
+    post:
+      security: []
+      responses:
+        "201": {description: created}
+  /internal/orders/sync:
+    post:
+      security: [{basic: []}]
+      responses:
+        "204": {description: done}
+  /login:
+    post:
+      tags: [Auth]
+      operationId: login
+      security: [{}]
+      responses:
+        "200": {description: ok}
+  /api/v1/user-profiles/{id}:
+    get:
+      responses:
+        "200": {description: ok}
+  /v1/auth/refresh:
+    post:
+      tags: [Auth]
+      responses:
+        "200": {description: ok}
+`
+
+func loadTestSpec(t *testing.T, src string) *Document {
+	t.Helper()
+	var d Document
+	if err := yaml.Unmarshal([]byte(src), &d); err != nil {
+		t.Fatal(err)
+	}
+	return &d
+}
+
+func TestRoute(t *testing.T) {
+	d := loadTestSpec(t, testSpec)
+	tests := []struct {
+		path, verb string
+		want       route
+		params     []string // in:name:required
+	}{
+		{
+			path: "/orders/{orderId}", verb: "GET",
+			want: route{pkg: "shop", endpointType: "private", endpoint: "/orders/:orderId", verb: "GET",
+				ucMethod: "GetOrder", method: "getOrder", tag: "Shop",
+				note: "/orders/{orderId} is served at /shop/orders/{orderId} (the /shop group plus the rest of the path); mount any prefix on the Echo group"},
+			// path-level params come first; the operation's verbose overrides the path's
+			params: []string{"path:orderId:true", "query:verbose:true", "query:limit:false"},
+		},
+		{
+			path: "/orders", verb: "POST",
+			want: route{pkg: "orders", endpointType: "public", verb: "POST",
+				ucMethod: "PostOrders", method: "postOrders", tag: "Orders"},
+		},
+		{
+			path: "/internal/orders/sync", verb: "POST",
+			want: route{pkg: "orders", endpointType: "internal", endpoint: "/sync", verb: "POST",
+				ucMethod: "PostInternalOrdersSync", method: "postInternalOrdersSync", tag: "Orders"},
+		},
+		{
+			path: "/login", verb: "POST",
+			want: route{pkg: "auth", endpointType: "public", endpoint: "/login", verb: "POST",
+				ucMethod: "Login", method: "login", tag: "Auth",
+				note: "/login is served at /auth/login (the /auth group plus the rest of the path); mount any prefix on the Echo group"},
+		},
+		{
+			// no tag: api/version prefixes are skipped, the name is normalized
+			path: "/api/v1/user-profiles/{id}", verb: "GET",
+			want: route{pkg: "userprofiles", endpointType: "private", endpoint: "/:id", verb: "GET",
+				ucMethod: "GetApiV1UserProfilesByID", method: "getApiV1UserProfilesByID", tag: "Userprofiles",
+				note: "/api/v1/user-profiles/{id} is served at /userprofiles/{id} (the /userprofiles group plus the rest of the path); mount any prefix on the Echo group"},
+		},
+		{
+			// the tag's segment is the mount point, what follows is the endpoint
+			path: "/v1/auth/refresh", verb: "POST",
+			want: route{pkg: "auth", endpointType: "private", endpoint: "/refresh", verb: "POST",
+				ucMethod: "PostV1AuthRefresh", method: "postV1AuthRefresh", tag: "Auth",
+				note: "/v1/auth/refresh is served at /auth/refresh (the /auth group plus the rest of the path); mount any prefix on the Echo group"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.verb+" "+tt.path, func(t *testing.T) {
+			item := d.Paths[tt.path]
+			op := item.operations()[tt.verb]
+			got, err := d.route(tt.path, tt.verb, item, op)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var params []string
+			for _, p := range got.params {
+				params = append(params, fmt.Sprintf("%s:%s:%v", p.In, p.Name, p.Required))
+			}
+			if !reflect.DeepEqual(params, tt.params) {
+				t.Errorf("params = %v, want %v", params, tt.params)
+			}
+			got.op, got.params = nil, nil
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("route =\n %+v\nwant\n %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRouteRejectsUnmountablePaths(t *testing.T) {
+	d := loadTestSpec(t, testSpec)
+	tests := []struct {
+		path    string
+		secured string
+		wantErr string
+	}{
+		{"/{id}", "", "add a tag"},
+		{"/v1/{id}", "", "add a tag"},
+		{"/2fa", "", "add a tag"},
+		{"/orders", "basic", "/internal/<pkg>"},
+	}
+	for _, tt := range tests {
+		op := &Operation{OperationID: "op"}
+		if tt.secured != "" {
+			op.Security = &[]Requirement{{tt.secured: nil}}
+		}
+		_, err := d.route(tt.path, "GET", PathItem{Get: op}, op)
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("route(%s) error = %v, want %q", tt.path, err, tt.wantErr)
+		}
+	}
+}
+
+func TestRefs(t *testing.T) {
+	d := loadTestSpec(t, testSpec)
+
+	p, err := d.parameter(&Parameter{Ref: "#/components/parameters/OrderID"})
+	if err != nil || p.Name != "orderId" || p.In != "path" {
+		t.Errorf("nested parameter ref = %+v, %v", p, err)
+	}
+	if _, err := d.parameter(&Parameter{Ref: "#/components/parameters/Nope"}); err == nil {
+		t.Error("missing parameter ref: want error")
+	}
+	if _, err := d.parameter(&Parameter{Ref: "other.yaml#/components/parameters/OrderID"}); err == nil {
+		t.Error("external ref: want error")
+	}
+
+	d.Components.Parameters["Loop"] = &Parameter{Ref: "#/components/parameters/Loop2"}
+	d.Components.Parameters["Loop2"] = &Parameter{Ref: "#/components/parameters/Loop"}
+	if _, err := d.parameter(&Parameter{Ref: "#/components/parameters/Loop"}); err == nil || !strings.Contains(err.Error(), "cyclic $ref") {
+		t.Errorf("cyclic parameter ref error = %v", err)
+	}
+	d.Components.RequestBodies = map[string]*RequestBody{"Self": {Ref: "#/components/requestBodies/Self"}}
+	if _, err := d.requestBody(&RequestBody{Ref: "#/components/requestBodies/Self"}); err == nil || !strings.Contains(err.Error(), "cyclic $ref") {
+		t.Errorf("cyclic requestBody ref error = %v", err)
+	}
+	d.Components.Responses["Self"] = &Response{Ref: "#/components/responses/Self"}
+	if _, err := d.response(&Response{Ref: "#/components/responses/Self"}); err == nil || !strings.Contains(err.Error(), "cyclic $ref") {
+		t.Errorf("cyclic response ref error = %v", err)
+	}
+
+	s, err := d.successSchema(d.Paths["/orders/{orderId}"].Get)
+	if err != nil || s == nil || s.Ref != "#/components/schemas/Order" {
+		t.Errorf("successSchema through a response ref = %+v, %v", s, err)
+	}
+	if s, err := d.successSchema(d.Paths["/orders"].Post); err != nil || s != nil {
+		t.Errorf("successSchema without content = %+v, %v", s, err)
+	}
+}
+
+func TestSuccessStatus(t *testing.T) {
+	op := func(codes ...string) *Operation {
+		o := &Operation{Responses: map[string]*Response{}}
+		for _, c := range codes {
+			o.Responses[c] = &Response{}
+		}
+		return o
+	}
+	tests := []struct {
+		op       *Operation
+		withResp bool
+		want     int
+	}{
+		{op("200", "400"), true, 200},
+		{op("404", "201", "202"), true, 201},
+		{op("204"), false, 204},
+		{op("204"), true, 0},          // 204 cannot carry the Response
+		{op("204", "200"), true, 200}, // the lowest one that can
+		{op("default"), true, 0},
+		{op("2XX"), true, 0},
+	}
+	for _, tt := range tests {
+		if got := successStatus(tt.op, tt.withResp); got != tt.want {
+			t.Errorf("successStatus(%v, %v) = %d, want %d", tt.op.Responses, tt.withResp, got, tt.want)
+		}
+	}
+}
+
+func TestJSONSchema(t *testing.T) {
+	s := &Schema{Type: "object"}
+	tests := []struct {
+		content map[string]MediaType
+		want    *Schema
+	}{
+		{map[string]MediaType{"application/json": {Schema: s}, "text/plain": {}}, s},
+		{map[string]MediaType{"application/problem+json": {Schema: s}}, s},
+		{map[string]MediaType{"text/csv": {Schema: s}}, nil},
+		{nil, nil},
+	}
+	for _, tt := range tests {
+		if got := jsonSchema(tt.content); got != tt.want {
+			t.Errorf("jsonSchema(%v) = %v, want %v", tt.content, got, tt.want)
+		}
+	}
+}
+
+func TestSchemaType(t *testing.T) {
+	tests := []struct {
+		src  string
+		want schemaType
+	}{
+		{`type: string`, "string"},
+		{`type: [integer, "null"]`, "integer"},
+		{`type: ["null", boolean]`, "boolean"},
+	}
+	for _, tt := range tests {
+		var s Schema
+		if err := yaml.Unmarshal([]byte(tt.src), &s); err != nil {
+			t.Fatal(err)
+		}
+		if s.Type != tt.want {
+			t.Errorf("%s: type = %q, want %q", tt.src, s.Type, tt.want)
+		}
+	}
+}
+
+func TestRules(t *testing.T) {
+	d := loadTestSpec(t, testSpec)
+	maxLen := 255
+	d.Components.Schemas["Email"] = &Schema{Type: "string", Format: "email", MaxLength: &maxLen}
+	tc := &types{doc: d}
+	tests := []struct {
+		src      string
+		required bool
+		want     string
+	}{
+		{`type: string`, false, ""},
+		{`type: string`, true, "required"},
+		{`{type: string, minLength: 3, maxLength: 50}`, false, "omitempty,min=3,max=50"},
+		{`{$ref: '#/components/schemas/Email'}`, true, "required,max=255,email"},
+		{`{type: integer, minimum: 1, maximum: 100}`, true, "required,min=1,max=100"},
+		{`{type: number, minimum: 0.5}`, false, "omitempty,min=0.5"},
+		{`{type: string, enum: [draft, sent]}`, false, "omitempty,oneof=draft sent"},
+		{`{type: string, enum: [on hold, sent]}`, false, ""}, // oneof cannot hold spaces
+		{`{type: boolean, enum: [true]}`, false, ""},
+	}
+	for _, tt := range tests {
+		var s Schema
+		if err := yaml.Unmarshal([]byte(tt.src), &s); err != nil {
+			t.Fatal(err)
+		}
+		if got := tc.rules(&s, tt.required); got != tt.want {
+			t.Errorf("rules(%s, %v) = %q, want %q", tt.src, tt.required, got, tt.want)
+		}
+	}
+
+	s := d.Components.Schemas["Order"]
+	s.Required = []string{"id"}
+	if got := tc.required(&Schema{AllOf: []*Schema{{Ref: "#/components/schemas/Order"}}}); !got["id"] {
+		t.Errorf("required through allOf = %v, want id", got)
+	}
+}
@@ -0,0 +1,218 @@
+package openapi
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/AndreeJait/ntaps/gen/usecase"
+	"github.com/AndreeJait/ntaps/internal/util"
+)
+
+// types turns schemas into Go types, writing every struct it needs into one
+// dto.go. Component schemas keep their name; inline objects are named after
+// where they appear (e.g. CreateOrderRequestAddress).
+type types struct {
+	doc  *Document
+	path string // dto.go
+	pkg  string
+	done map[string]bool
+}
+
+func (t *types) goType(s *Schema, hint string) (string, error) {
+	if s == nil {
+		return "any", nil
+	}
+	if s.Ref != "" {
+		name, err := refName(s.Ref, "schemas")
+		if err != nil {
+			return "", err
+		}
+		target, ok := t.doc.Components.Schemas[name]
+		if !ok {
+			return "", fmt.Errorf("$ref %q not found", s.Ref)
+		}
+		if !isObject(target) {
+			return t.goType(target, util.ExportedName(name))
+		}
+		return t.structType(target, util.ExportedName(name))
+	}
+	if isObject(s) {
+		return t.structType(s, hint)
+	}
+
+	switch s.Type {
+	case "array":
+		elem, err := t.goType(s.Items, hint+"Item")
+		return "[]" + elem, err
+	case "string":
+		switch s.Format {
+		case "date-time", "date":
+			return "time.Time", nil
+		case "binary", "byte":
+			return "[]byte", nil
+		}
+		return "string", nil
+	case "integer":
+		switch s.Format {
+		case "int64":
+			return "int64", nil
+		case "int32":
+			return "int32", nil
+		}
+		return "int", nil
+	case "number":
+		if s.Format == "float" {
+			return "float32", nil
+		}
+		return "float64", nil
+	case "boolean":
+		return "bool", nil
+	case "object":
+		return "map[string]any", nil
+	}
+	return "any", nil // oneOf/anyOf/untyped
+}
+
+// structType ensures `type <name> struct` holds s's properties.
+func (t *types) structType(s *Schema, name string) (string, error) {
+	if t.done[name] {
+		return name, nil
+	}
+	t.done[name] = true
+	fields, err := t.fields(s, name)
+	if err != nil {
+		return "", err
+	}
+	return name, usecase.EnsureDTOType(t.path, t.pkg, name, fields)
+}
+
+// fields lists s's properties (allOf members merged) as json-tagged fields.
+func (t *types) fields(s *Schema, owner string) ([]util.StructField, error) {
+	if s != nil && s.Ref != "" { // inline types nested in a component are named after it
+		if name, err := refName(s.Ref, "schemas"); err == nil {
+			owner = util.ExportedName(name)
+		}
+	}
+	var out []util.StructField
+	for _, p := range t.properties(s) {
+		name := util.ExportedName(p.Name)
+		typ, err := t.goType(p.Schema, owner+name)
+		if err != nil {
+			return nil, fmt.Errorf("%s.%s: %w", owner, p.Name, err)
+		}
+		if typ == owner { // self-reference needs indirection
+			typ = "*" + typ
+		}
+		out = append(out, util.StructField{Name: name, Type: typ, Tag: fmt.Sprintf(`json:"%s"`, p.Name)})
+	}
+	return out, nil
+}
+
+func (t *types) properties(s *Schema) properties {
+	if s == nil {
+		return nil
+	}
+	if s.Ref != "" {
+		name, err := refName(s.Ref, "schemas")
+		if err != nil {
+			return nil
+		}
+		return t.properties(t.doc.Components.Schemas[name])
+	}
+	out := append(properties{}, s.Properties...)
+	for _, sub := range s.AllOf {
+		out = append(out, t.properties(sub)...)
+	}
+	return out
+}
+
+// isObject reports whether s becomes a struct (has, or merges, properties).
+func isObject(s *Schema) bool {
+	if s == nil {
+		return false
+	}
+	return len(s.Properties) > 0 || len(s.AllOf) > 0
+}
+
+// resolve follows s's $ref to the component schema, if any.
+func (t *types) resolve(s *Schema) *Schema {
+	for i := 0; s != nil && s.Ref != "" && i < 32; i++ {
+		name, err := refName(s.Ref, "schemas")
+		if err != nil {
+			return nil
+		}
+		s = t.doc.Components.Schemas[name]
+	}
+	return s
+}
+
+// required lists the required properties of s, allOf members included.
+func (t *types) required(s *Schema) map[string]bool {
+	out := map[string]bool{}
+	if s = t.resolve(s); s == nil {
+		return out
+	}
+	for _, n := range s.Required {
+		out[n] = true
+	}
+	for _, sub := range s.AllOf {
+		for n := range t.required(sub) {
+			out[n] = true
+		}
+	}
+	return out
+}
+
+// rules turns s's constraints into a validate tag value the generated
+// Validate() understands ("" when there are none): required, minLength/
+// maxLength or minimum/maximum as min/max, format email, enum as oneof.
+func (t *types) rules(s *Schema, required bool) string {
+	var out []string
+	if s = t.resolve(s); s != nil {
+		num := func(f float64) string { return strconv.FormatFloat(f, 'f', -1, 64) }
+		switch s.Type {
+		case "string":
+			if s.MinLength != nil {
+				out = append(out, "min="+strconv.Itoa(*s.MinLength))
+			}
+			if s.MaxLength != nil {
+				out = append(out, "max="+strconv.Itoa(*s.MaxLength))
+			}
+			if s.Format == "email" {
+				out = append(out, "email")
+			}
+		case "integer", "number":
+			if s.Minimum != nil {
+				out = append(out, "min="+num(*s.Minimum))
+			}
+			if s.Maximum != nil {
+				out = append(out, "max="+num(*s.Maximum))
+			}
+		}
+		if len(s.Enum) > 0 && (s.Type == "string" || s.Type == "integer" || s.Type == "number") {
+			spaced := false
+			for _, e := range s.Enum {
+				spaced = spaced || strings.ContainsAny(e, " \t,")
+			}
+			if !spaced {
+				out = append(out, "oneof="+strings.Join(s.Enum, " "))
+			}
+		}
+	}
+	switch {
+	case required:
+		out = append([]string{"required"}, out...)
+	case len(out) > 0:
+		out = append([]string{"omitempty"}, out...)
+	}
+	return strings.Join(out, ",")
+}
+
+// withRules appends validate:"<rules>" to tag.
+func withRules(tag, rules string) string {
+	if rules == "" {
+		return tag
+	}
+	return tag + fmt.Sprintf(` validate:"%s"`, rules)
+}
@@ -1,35 +1,49 @@
 package outbound
 
 import (
-	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
 
+	"github.com/AndreeJait/ntaps/gen/usecase"
 	"github.com/AndreeJait/ntaps/internal/paths"
+	"github.com/AndreeJait/ntaps/internal/tmpl"
 	"github.com/AndreeJait/ntaps/internal/util"
 )
 
 func ensureOutboundDTO(pkg, method string, withParam, withResp bool) error {
 	path := filepath.Join(paths.OutboundRootPath, pkg, "dto.go")
 
-	if _, err := os.Stat(path); os.IsNotExist(err) {
+	if _, err := util.Stat(path); os.IsNotExist(err) {
 		if err := util.WriteGoFile(path, "package "+pkg+"\n\n"); err != nil {
 			return err
 		}
 	}
-	raw, err := os.ReadFile(path)
+	raw, err := util.ReadFile(path)
 	if err != nil {
 		return err
 	}
 	src := string(raw)
 
-	if withParam && !strings.Contains(src, "type "+method+"Request struct") {
-		src += fmt.Sprintf("\n// %sRequest generated by ntaps\ntype %sRequest struct {\n\t// TODO: define fields\n}\n", method, method)
-	}
-	if withResp && !strings.Contains(src, "type "+method+"Response struct") {
-		src += fmt.Sprintf("\n// %sResponse generated by ntaps\ntype %sResponse struct {\n\t// TODO: define fields\n}\n", method, method)
+	for _, name := range []string{
+		util.Conditional(withParam, method+"Request"),
+		util.Conditional(withResp, method+"Response"),
+	} {
+		if name == "" || strings.Contains(src, "type "+name+" struct") {
+			continue
+		}
+		s, err := tmpl.Render("dto_struct.tmpl", tmpl.DTO{Pkg: pkg, Name: name, Comment: name + " generated by ntaps"})
+		if err != nil {
+			return err
+		}
+		src += s
 	}
 
 	return util.WriteGoFile(path, src)
 }
+
+// AddFields adds fields to `type <name> struct` in the outbound package's
+// dto.go.
+func AddFields(pkg, name string, fields []util.StructField) error {
+	return usecase.EnsureDTOType(filepath.Join(paths.OutboundRootPath, pkg, "dto.go"), pkg, name, fields)
+}
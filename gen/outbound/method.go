@@ -2,11 +2,11 @@ package outbound
 
 import (
 	"fmt"
-	"os"
 	"path/filepath"
 	"strings"
 
 	"github.com/AndreeJait/ntaps/internal/paths"
+	"github.com/AndreeJait/ntaps/internal/tmpl"
 	"github.com/AndreeJait/ntaps/internal/util"
 )
 
@@ -14,7 +14,7 @@ func ensureOutboundPortHasMethod(pkg, method string, withParam, withResp bool) e
 	iface := ifaceName(pkg)
 	path := filepath.Join(paths.OutboundRootPath, pkg, "port.go")
 
-	b, err := os.ReadFile(path)
+	b, err := util.ReadFile(path)
 	if err != nil {
 		return err
 	}
@@ -29,15 +29,7 @@ func ensureOutboundPortHasMethod(pkg, method string, withParam, withResp bool) e
 	}
 
 	// method signature
-	req := "ctx context.Context"
-	if withParam {
-		req += ", req " + method + "Request"
-	}
-	ret := "error"
-	if withResp {
-		ret = "(" + method + "Response, error)"
-	}
-	newLine := fmt.Sprintf("\t%s(%s) %s\n", method, req, ret)
+	newLine := "\t" + signature(method, withParam, withResp) + "\n"
 
 	start := strings.Index(src, "type "+iface+" interface {")
 	if start == -1 {
@@ -61,10 +53,9 @@ func ensureOutboundPortHasMethod(pkg, method string, withParam, withResp bool) e
 }
 
 func ensureOutboundImplHasMethod(pkg, method string, withParam, withResp bool) error {
-	mod := util.ModulePathGuess()
 	path := filepath.Join(paths.OutboundRootPath, pkg, "impl.go")
 
-	raw, err := os.ReadFile(path)
+	raw, err := util.ReadFile(path)
 	if err != nil {
 		return err
 	}
@@ -74,7 +65,7 @@ func ensureOutboundImplHasMethod(pkg, method string, withParam, withResp bool) e
 	required := []string{
 		`"context"`,
 		`"github.com/AndreeJait/go-utility/tracer"`,
-		fmt.Sprintf(`"%s/internal/infrastructure/config"`, mod),
+		fmt.Sprintf(`"%s"`, util.ImportPath(paths.ConfigDir)),
 		`"github.com/AndreeJait/go-utility/loggerw"`,
 	}
 	for _, imp := range required {
@@ -86,27 +77,11 @@ func ensureOutboundImplHasMethod(pkg, method string, withParam, withResp bool) e
 		return util.WriteGoFile(path, src)
 	}
 
-	args := "ctx context.Context"
-	if withParam {
-		args += ", req " + method + "Request"
-	}
-	ret := "error"
-	retBody := "// TODO: implement\n\treturn nil"
-	if withResp {
-		ret = "(" + method + "Response, error)"
-		retBody = "var resp " + method + "Response\n\t// TODO: implement\n\treturn resp, nil"
+	methodSrc, err := tmpl.Render("outbound_method.tmpl", templateData(pkg, method, withParam, withResp))
+	if err != nil {
+		return err
 	}
 
-	methodSrc := fmt.Sprintf(`
-
-func (i *impl) %s(%s) %s {
-	span, ctx := tracer.StartSpan(ctx, tracer.GetFuncName(i.%s))
-	defer span.End()
-
-	%s
-}
-`, method, args, ret, method, retBody)
-
 	src += methodSrc
 	return util.WriteGoFile(path, src)
 }
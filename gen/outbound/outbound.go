@@ -1,6 +1,10 @@
 package outbound
 
 import (
+	"fmt"
+
+	"github.com/AndreeJait/ntaps/internal/paths"
+	"github.com/AndreeJait/ntaps/internal/tmpl"
 	"github.com/AndreeJait/ntaps/internal/util"
 )
 
@@ -30,3 +34,29 @@ func Run(pkg, method string, withParam, withResp bool) error {
 func ifaceName(pkg string) string {
 	return util.ToPascalCase(pkg)
 }
+
+// templateData builds what outbound_*.tmpl receives.
+func templateData(pkg, method string, withParam, withResp bool) tmpl.Outbound {
+	return tmpl.Outbound{
+		Pkg:          pkg,
+		Iface:        ifaceName(pkg),
+		Method:       method,
+		WithParam:    withParam,
+		WithResponse: withResp,
+		Signature:    signature(method, withParam, withResp),
+		ConfigImport: util.ImportPath(paths.ConfigDir),
+	}
+}
+
+// signature renders "<Method>(ctx context.Context[, req <Method>Request]) <ret>".
+func signature(method string, withParam, withResp bool) string {
+	req := "ctx context.Context"
+	if withParam {
+		req += ", req " + method + "Request"
+	}
+	ret := "error"
+	if withResp {
+		ret = "(" + method + "Response, error)"
+	}
+	return fmt.Sprintf("%s(%s) %s", method, req, ret)
+}
@@ -1,20 +1,19 @@
 package outbound
 
 import (
-	"fmt"
 	"os"
 	"path/filepath"
 
 	"github.com/AndreeJait/ntaps/internal/paths"
+	"github.com/AndreeJait/ntaps/internal/tmpl"
 	"github.com/AndreeJait/ntaps/internal/util"
 )
 
 func ensureOutboundPkg(pkg string) error {
-	mod := util.ModulePathGuess()
 	dir := filepath.Join(paths.OutboundRootPath, pkg)
 
-	if _, err := os.Stat(dir); os.IsNotExist(err) {
-		if err := os.MkdirAll(dir, 0o755); err != nil {
+	if _, err := util.Stat(dir); os.IsNotExist(err) {
+		if err := util.MkdirAll(dir); err != nil {
 			return err
 		}
 	}
@@ -26,23 +25,11 @@ func ensureOutboundPkg(pkg string) error {
 
 	// impl.go
 	implPath := filepath.Join(dir, "impl.go")
-	if _, err := os.Stat(implPath); os.IsNotExist(err) {
-		body := fmt.Sprintf(`package %s
-
-import (
-	"%s/internal/infrastructure/config"
-	"github.com/AndreeJait/go-utility/loggerw"
-)
-
-type impl struct {
-	logger loggerw.Logger
-	cfg    *config.Config
-}
-
-func New%[2]sW(log loggerw.Logger, cfg *config.Config) %[2]s {
-	return &impl{logger: log, cfg: cfg}
-}
-`, pkg, ifaceName(pkg), mod)
+	if _, err := util.Stat(implPath); os.IsNotExist(err) {
+		body, err := tmpl.Render("outbound_impl.tmpl", templateData(pkg, "", false, false))
+		if err != nil {
+			return err
+		}
 		return util.WriteGoFile(implPath, body)
 	}
 	return nil
@@ -50,12 +37,11 @@ func New%[2]sW(log loggerw.Logger, cfg *config.Config) %[2]s {
 
 func ensureOutboundPort(pkg string) error {
 	path := filepath.Join(paths.OutboundRootPath, pkg, "port.go")
-	if _, err := os.Stat(path); os.IsNotExist(err) {
-		body := fmt.Sprintf(`package %s
-
-type %s interface {
-}
-`, pkg, ifaceName(pkg))
+	if _, err := util.Stat(path); os.IsNotExist(err) {
+		body, err := tmpl.Render("outbound_port.tmpl", templateData(pkg, "", false, false))
+		if err != nil {
+			return err
+		}
 		return util.WriteGoFile(path, body)
 	}
 	return nil
@@ -0,0 +1,30 @@
+package outbound
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"github.com/AndreeJait/ntaps/internal/paths"
+	"github.com/AndreeJait/ntaps/internal/util"
+)
+
+// Remove deletes an outbound adapter package. Outbounds are not wired into
+// DI by ntaps, so the only thing to check is that nobody imports it.
+func Remove(pkg string, force bool) error {
+	dir := filepath.Join(paths.OutboundRootPath, pkg)
+	if filepath.Clean(dir) == filepath.Clean(paths.RepoRootPath) {
+		return fmt.Errorf("%s is the repository root, not an outbound adapter", dir)
+	}
+	if _, err := util.Stat(dir); os.IsNotExist(err) {
+		return fmt.Errorf("outbound %q not found in %s", pkg, paths.OutboundRootPath)
+	}
+
+	refs := util.FindReferences(regexp.MustCompile(`"`+regexp.QuoteMeta(util.ImportPath(paths.OutboundRootPath, pkg))+`"`), dir)
+	if err := util.GuardReferences("outbound "+pkg, refs, force); err != nil {
+		return err
+	}
+
+	return util.RemoveAll(dir)
+}
@@ -0,0 +1,199 @@
+package rename
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/AndreeJait/ntaps/internal/util"
+)
+
+// edit replaces src[pos:end] with text.
+type edit struct {
+	pos, end int
+	text     string
+}
+
+// fileRewrite returns the edits one parsed file needs.
+type fileRewrite func(path string, fset *token.FileSet, f *ast.File, src string) []edit
+
+// rewriteAll runs rw over every Go file of the project and writes the
+// files that changed. moves maps old package dirs to new ones; files in
+// them are written to the new dir and the old dir is removed.
+func rewriteAll(rw fileRewrite, moves map[string]string) (changed []string, err error) {
+	for _, path := range goFiles() {
+		raw, err := util.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		src := string(raw)
+		fset := token.NewFileSet()
+		f, err := parser.ParseFile(fset, path, src, parser.ParseComments)
+		if err != nil {
+			continue // not ours to fix; build will tell
+		}
+		out := applyEdits(src, rw(path, fset, f, src))
+
+		dest := path
+		if to, ok := moves[filepath.Dir(path)]; ok {
+			dest = filepath.Join(to, filepath.Base(path))
+		}
+		if dest == path && out == src {
+			continue
+		}
+		if err := util.WriteGoFile(dest, out); err != nil {
+			return changed, err
+		}
+		changed = append(changed, dest)
+	}
+
+	for from, to := range moves {
+		entries, _ := os.ReadDir(from)
+		for _, e := range entries {
+			if e.IsDir() || strings.HasSuffix(e.Name(), ".go") {
+				continue
+			}
+			raw, err := util.ReadFile(filepath.Join(from, e.Name()))
+			if err != nil {
+				continue
+			}
+			if err := util.WriteFile(filepath.Join(to, e.Name()), string(raw)); err != nil {
+				return changed, err
+			}
+		}
+		if err := util.RemoveAll(from); err != nil {
+			return changed, err
+		}
+	}
+	sort.Strings(changed)
+	return changed, nil
+}
+
+// scan runs rw over every Go file without writing, so a rename can be
+// validated (target free, source found) before anything changes.
+func scan(rw fileRewrite) {
+	for _, path := range goFiles() {
+		if raw, err := util.ReadFile(path); err == nil {
+			fset := token.NewFileSet()
+			if f, err := parser.ParseFile(fset, path, raw, parser.ParseComments); err == nil {
+				rw(path, fset, f, string(raw))
+			}
+		}
+	}
+}
+
+func goFiles() []string {
+	var files []string
+	_ = filepath.WalkDir(".", func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() {
+			switch d.Name() {
+			case ".git", ".ntaps", "vendor", "node_modules":
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if strings.HasSuffix(path, ".go") {
+			files = append(files, path)
+		}
+		return nil
+	})
+	return files
+}
+
+// applyEdits applies non-overlapping edits; duplicates (two rules hitting
+// the same identifier) are applied once.
+func applyEdits(src string, edits []edit) string {
+	sort.Slice(edits, func(i, j int) bool { return edits[i].pos > edits[j].pos })
+	last := -1
+	for _, e := range edits {
+		if e.pos == last {
+			continue
+		}
+		src = src[:e.pos] + e.text + src[e.end:]
+		last = e.pos
+	}
+	return src
+}
+
+func identEdit(fset *token.FileSet, id *ast.Ident, name string) edit {
+	pos := fset.Position(id.Pos()).Offset
+	return edit{pos: pos, end: pos + len(id.Name), text: name}
+}
+
+// commentEdits rewrites comment text with fix (swagger blocks, docs).
+func commentEdits(fset *token.FileSet, groups []*ast.CommentGroup, src string, fix func(string) string) []edit {
+	var out []edit
+	for _, g := range groups {
+		if g == nil {
+			continue
+		}
+		for _, c := range g.List {
+			pos := fset.Position(c.Pos()).Offset
+			end := pos + len(c.Text)
+			if text := fix(src[pos:end]); text != src[pos:end] {
+				out = append(out, edit{pos: pos, end: end, text: text})
+			}
+		}
+	}
+	return out
+}
+
+// importName returns the name a file uses for importPath ("" when it does
+// not import it) and whether that name is an explicit alias.
+func importName(f *ast.File, importPath string) (name string, explicit bool) {
+	for _, imp := range f.Imports {
+		p, _ := strconv.Unquote(imp.Path.Value)
+		if p != importPath {
+			continue
+		}
+		if imp.Name != nil {
+			return imp.Name.Name, true
+		}
+		return filepath.Base(p), false
+	}
+	return "", false
+}
+
+// recvName returns the receiver variable and type name of a method.
+func recvName(fd *ast.FuncDecl) (name, typ string) {
+	if fd.Recv == nil || len(fd.Recv.List) == 0 {
+		return "", ""
+	}
+	r := fd.Recv.List[0]
+	t := r.Type
+	if star, ok := t.(*ast.StarExpr); ok {
+		t = star.X
+	}
+	if id, ok := t.(*ast.Ident); ok {
+		typ = id.Name
+	}
+	if len(r.Names) > 0 {
+		name = r.Names[0].Name
+	}
+	return name, typ
+}
+
+// replaceWords returns a comment fixer replacing whole words old -> new.
+func replaceWords(m map[string]string) func(string) string {
+	if len(m) == 0 {
+		return func(s string) string { return s }
+	}
+	var words []string
+	for w := range m {
+		words = append(words, regexp.QuoteMeta(w))
+	}
+	re := regexp.MustCompile(`\b(` + strings.Join(words, "|") + `)\b`)
+	return func(s string) string {
+		return re.ReplaceAllStringFunc(s, func(w string) string { return m[w] })
+	}
+}
@@ -0,0 +1,181 @@
+package rename
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+
+	"github.com/AndreeJait/ntaps/internal/paths"
+	"github.com/AndreeJait/ntaps/internal/util"
+)
+
+// Layers a package can live in, in the order they are renamed.
+var Layers = []string{"usecase", "repository", "outbound", "handler"}
+
+// layerRenames returns the package dir of pkg in layer, the identifiers
+// ntaps derives from the package name anywhere in the project (DI fields,
+// constructors, usecase repo ports) and those only exported by the package.
+func layerRenames(layer, from, to string) (dir string, global, exported map[string]string) {
+	pf, pt := util.ToPascalCase(from), util.ToPascalCase(to)
+	switch layer {
+	case "usecase":
+		return filepath.Join(paths.RootUsecaseDir, from),
+			map[string]string{pf + "Uc": pt + "Uc"}, nil
+	case "repository":
+		return filepath.Join(paths.RepoPgPath, from),
+			map[string]string{
+				pf + "Repo":                     pt + "Repo",
+				util.ToCamelCase(from) + "Repo": util.ToCamelCase(to) + "Repo",
+				"New" + pf + "Repository":       "New" + pt + "Repository",
+			}, nil
+	case "outbound":
+		return filepath.Join(paths.OutboundRootPath, from), nil,
+			map[string]string{pf: pt, "New" + pf + "W": "New" + pt + "W"}
+	case "handler":
+		return filepath.Join(paths.HandlerRootHTTPDir, from),
+			map[string]string{"New" + pf + "Handler": "New" + pt + "Handler"}, nil
+	}
+	return "", nil, nil
+}
+
+// Package renames pkg from -> to in the given layers (all layers where it
+// exists when none are given): the directory, package clause, import
+// paths, qualified references and the DI names derived from it.
+func Package(from, to string, layers []string) ([]string, error) {
+	if from == to {
+		return nil, fmt.Errorf("--from and --to are the same")
+	}
+	if !token.IsIdentifier(to) || token.IsExported(to) {
+		return nil, fmt.Errorf("%q is not a valid lower-case package name", to)
+	}
+	explicit := len(layers) > 0
+	if !explicit {
+		layers = Layers
+	}
+
+	moves := map[string]string{}               // old dir -> new dir
+	imports := map[string]string{}             // old import path -> new
+	global := map[string]string{}              // idents renamed everywhere
+	exported := map[string]map[string]string{} // import path -> qualified renames
+	for _, l := range layers {
+		dir, g, e := layerRenames(l, from, to)
+		if dir == "" {
+			return nil, fmt.Errorf("unknown layer %q (want one of %v)", l, Layers)
+		}
+		if _, err := util.Stat(dir); os.IsNotExist(err) {
+			if explicit {
+				return nil, fmt.Errorf("%s package %q not found in %s", l, from, filepath.Dir(dir))
+			}
+			continue
+		}
+		newDir := filepath.Join(filepath.Dir(dir), to)
+		if _, err := util.Stat(newDir); err == nil {
+			return nil, fmt.Errorf("%s already exists", newDir)
+		}
+		moves[filepath.Clean(dir)] = newDir
+		imports[util.ImportPath(dir)] = util.ImportPath(newDir)
+		for k, v := range g {
+			global[k] = v
+		}
+		exported[util.ImportPath(dir)] = e
+	}
+	if len(moves) == 0 {
+		return nil, fmt.Errorf("package %q not found in any layer", from)
+	}
+
+	qualifiedComment := regexp.MustCompile(`\b` + regexp.QuoteMeta(from) + `\.([A-Z])`)
+
+	rw := func(path string, fset *token.FileSet, f *ast.File, src string) []edit {
+		var edits []edit
+		dir := filepath.Clean(filepath.Dir(path))
+
+		var local map[string]string
+		if _, moving := moves[dir]; moving {
+			edits = append(edits, identEdit(fset, f.Name, to))
+			local = exported[util.ImportPath(dir)]
+		}
+
+		// import path + the default-named qualifier for each renamed import
+		qualified := map[string]map[string]string{} // qualifier -> exported renames
+		for _, imp := range f.Imports {
+			p, _ := strconv.Unquote(imp.Path.Value)
+			newPath, ok := imports[p]
+			if !ok {
+				continue
+			}
+			pos := fset.Position(imp.Path.Pos()).Offset
+			edits = append(edits, edit{pos: pos, end: pos + len(imp.Path.Value), text: strconv.Quote(newPath)})
+			if imp.Name == nil {
+				qualified[filepath.Base(p)] = exported[p]
+			} else {
+				qualified["\x00"+imp.Name.Name] = exported[p]
+			}
+		}
+
+		// inside the package, exported renames (the outbound interface) must
+		// not touch struct fields, keys or selectors that share the name
+		skip := map[*ast.Ident]bool{}
+		if local != nil {
+			ast.Inspect(f, func(n ast.Node) bool {
+				switch v := n.(type) {
+				case *ast.Field:
+					for _, id := range v.Names {
+						skip[id] = true
+					}
+				case *ast.KeyValueExpr:
+					if id, ok := v.Key.(*ast.Ident); ok {
+						skip[id] = true
+					}
+				case *ast.SelectorExpr:
+					skip[v.Sel] = true
+				}
+				return true
+			})
+		}
+
+		ast.Inspect(f, func(n ast.Node) bool {
+			switch v := n.(type) {
+			case *ast.SelectorExpr:
+				x, ok := v.X.(*ast.Ident)
+				if !ok {
+					return true
+				}
+				if ex, ok := qualified[x.Name]; ok {
+					edits = append(edits, identEdit(fset, x, to))
+					if newName, ok := ex[v.Sel.Name]; ok {
+						edits = append(edits, identEdit(fset, v.Sel, newName))
+					}
+				} else if ex, ok := qualified["\x00"+x.Name]; ok {
+					if newName, ok := ex[v.Sel.Name]; ok {
+						edits = append(edits, identEdit(fset, v.Sel, newName))
+					}
+				}
+			case *ast.Ident:
+				if newName, ok := global[v.Name]; ok {
+					edits = append(edits, identEdit(fset, v, newName))
+				} else if newName, ok := local[v.Name]; ok && !skip[v] {
+					edits = append(edits, identEdit(fset, v, newName))
+				}
+			}
+			return true
+		})
+
+		// swagger blocks reference DTOs as <pkg>.<Type>; DI fields carry a
+		// "// <Field>" doc comment
+		_, qualifies := qualified[from]
+		words := replaceWords(global)
+		edits = append(edits, commentEdits(fset, f.Comments, src, func(c string) string {
+			if qualifies {
+				c = qualifiedComment.ReplaceAllString(c, to+".${1}")
+			}
+			return words(c)
+		})...)
+		return edits
+	}
+
+	return rewriteAll(rw, moves)
+}
@@ -0,0 +1,295 @@
+// Package rename renames generated methods and packages across every layer
+// ntaps wires together. It edits identifiers by their go/ast position, so
+// strings and unrelated code that merely contain the old name are left
+// alone; only comments ntaps itself writes (swagger blocks, docs) and the
+// handler's "success ..." message are rewritten textually.
+package rename
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/AndreeJait/ntaps/internal/paths"
+	"github.com/AndreeJait/ntaps/internal/util"
+)
+
+// method describes where a generated method lives and how other layers
+// reach it.
+type method struct {
+	dir      string
+	recvType string   // useCase | Repository | impl
+	iface    string   // interface in dir declaring the method ("" for repos)
+	dtos     []string // DTO suffixes: Request/Response or Param/Response
+	// callVia is the field other layers call it through,
+	// e.g. SendUc in h.uc.SendUc.<Method>(...) ("" when unknown).
+	callVia string
+	// foreignIfaces are interfaces in importing packages that re-declare
+	// the method, e.g. UserRepo in a usecase port.
+	foreignIfaces []string
+}
+
+// UsecaseMethod renames <pkg>.UseCase.<from> together with its DTOs and
+// the h.uc.<Pkg>Uc.<from> calls in handlers.
+func UsecaseMethod(pkg, from, to string) ([]string, error) {
+	return renameMethod(method{
+		dir:      filepath.Join(paths.RootUsecaseDir, pkg),
+		recvType: "useCase",
+		iface:    "UseCase",
+		dtos:     []string{"Request", "Response"},
+		callVia:  util.ToPascalCase(pkg) + "Uc",
+	}, from, to)
+}
+
+// RepoMethod renames a repository method, its Param/Response DTOs, the
+// method in every usecase <Repo> port and the u.<repo>Repo.<from> calls.
+func RepoMethod(pkg, from, to string) ([]string, error) {
+	return renameMethod(method{
+		dir:           filepath.Join(paths.RepoPgPath, pkg),
+		recvType:      "Repository",
+		dtos:          []string{"Param", "Response"},
+		callVia:       util.ToCamelCase(pkg) + "Repo",
+		foreignIfaces: []string{util.ToPascalCase(pkg) + "Repo", "Repo"},
+	}, from, to)
+}
+
+// OutboundMethod renames an outbound port method and its DTOs. ntaps does
+// not know which fields hold the outbound, so call sites are only reported.
+func OutboundMethod(pkg, from, to string) ([]string, error) {
+	return renameMethod(method{
+		dir:      filepath.Join(paths.OutboundRootPath, pkg),
+		recvType: "impl",
+		iface:    util.ToPascalCase(pkg),
+		dtos:     []string{"Request", "Response"},
+	}, from, to)
+}
+
+func renameMethod(m method, from, to string) ([]string, error) {
+	if from == to {
+		return nil, fmt.Errorf("--from and --to are the same")
+	}
+	if !token.IsIdentifier(to) || !token.IsExported(to) {
+		return nil, fmt.Errorf("%q is not an exported Go identifier", to)
+	}
+	if _, err := util.Stat(m.dir); os.IsNotExist(err) {
+		return nil, fmt.Errorf("package %s not found", m.dir)
+	}
+	importPath := util.ImportPath(m.dir)
+
+	dtoNames := map[string]string{}
+	for _, s := range m.dtos {
+		dtoNames[from+s] = to + s
+	}
+	// captures the suffix so "<from>Request" becomes "<to>Request"
+	dtoCommentRe := regexp.MustCompile(`\b` + regexp.QuoteMeta(from) + `(` + strings.Join(m.dtos, "|") + `)\b`)
+
+	renameDTOs := func(c string) string { return dtoCommentRe.ReplaceAllString(c, to+"${1}") }
+	renameWord := replaceWords(map[string]string{from: to})
+	fixDoc := func(c string) string { return renameWord(renameDTOs(c)) }
+
+	// a handler's swagger block names the operation humanized (@Summary
+	// Get Transaction, "success get transaction") and after the handler
+	// method (getTransaction), which follows the usecase method's name
+	lowerFrom, lowerTo := lowerFirst(from), lowerFirst(to)
+	humanFrom, humanTo := util.HumanizePascal(from), util.HumanizePascal(to)
+	renameSwagger := replaceWords(map[string]string{
+		from:                       to,
+		lowerFrom:                  lowerTo,
+		humanFrom:                  humanTo,
+		strings.ToLower(humanFrom): strings.ToLower(humanTo),
+	})
+	fixSwagger := func(c string) string { return renameSwagger(renameDTOs(c)) }
+
+	found, clash := false, false
+	var unresolved []string
+
+	rw := func(path string, fset *token.FileSet, f *ast.File, src string) []edit {
+		inPkg := filepath.Clean(filepath.Dir(path)) == filepath.Clean(m.dir)
+		alias, _ := importName(f, importPath)
+		var edits []edit
+		// docs rewritten beyond DTO names; one fix per comment group, so
+		// two rules never race for the same comment
+		docFix := map[*ast.CommentGroup]func(string) string{}
+
+		// handler methods calling the renamed method through callVia
+		var handlers []*ast.FuncDecl
+		handlerNames := map[string]bool{}
+		for _, d := range f.Decls {
+			fd, ok := d.(*ast.FuncDecl)
+			if !ok || inPkg || alias == "" || m.callVia == "" {
+				continue
+			}
+			if _, typ := recvName(fd); typ == "handler" {
+				handlerNames[fd.Name.Name] = true
+				if callsVia(fd.Body, m.callVia, from) {
+					handlers = append(handlers, fd)
+				}
+			}
+		}
+		renameHandler := false
+		successFrom := strconv.Quote("success " + strings.ToLower(humanFrom))
+		for _, fd := range handlers {
+			docFix[fd.Doc] = fixSwagger
+			// the response message repeats the @Success description
+			ast.Inspect(fd.Body, func(n ast.Node) bool {
+				if lit, ok := n.(*ast.BasicLit); ok && lit.Value == successFrom {
+					pos := fset.Position(lit.Pos()).Offset
+					edits = append(edits, edit{pos: pos, end: pos + len(lit.Value), text: strconv.Quote("success " + strings.ToLower(humanTo))})
+				}
+				return true
+			})
+			if fd.Name.Name == lowerFrom {
+				if handlerNames[lowerTo] {
+					clash = true
+				}
+				renameHandler = true
+			}
+		}
+
+		renameMember := func(fl *ast.FieldList) {
+			for _, fld := range fl.List {
+				for _, n := range fld.Names {
+					if n.Name == to {
+						clash = true
+					}
+					if n.Name == from {
+						edits = append(edits, identEdit(fset, n, to))
+						docFix[fld.Doc] = fixDoc
+					}
+				}
+			}
+		}
+
+		ast.Inspect(f, func(n ast.Node) bool {
+			switch v := n.(type) {
+			case *ast.TypeSpec:
+				it, ok := v.Type.(*ast.InterfaceType)
+				if !ok {
+					return true
+				}
+				if inPkg && v.Name.Name == m.iface {
+					renameMember(it.Methods)
+				}
+				if !inPkg && alias != "" {
+					for _, fi := range m.foreignIfaces {
+						if v.Name.Name == fi {
+							renameMember(it.Methods)
+						}
+					}
+				}
+
+			case *ast.FuncDecl:
+				recv, typ := recvName(v)
+				if renameHandler && typ == "handler" && v.Name.Name == lowerFrom {
+					edits = append(edits, identEdit(fset, v.Name, lowerTo))
+				}
+				if !inPkg || typ != m.recvType {
+					return true
+				}
+				if v.Name.Name == to {
+					clash = true
+				}
+				if v.Name.Name != from {
+					return true
+				}
+				found = true
+				edits = append(edits, identEdit(fset, v.Name, to))
+				docFix[v.Doc] = fixDoc
+				// tracer.GetFuncName(u.<from>) and other self references
+				ast.Inspect(v.Body, func(n ast.Node) bool {
+					if sel, ok := n.(*ast.SelectorExpr); ok && sel.Sel.Name == from {
+						if x, ok := sel.X.(*ast.Ident); ok && x.Name == recv {
+							edits = append(edits, identEdit(fset, sel.Sel, to))
+						}
+					}
+					return true
+				})
+
+			case *ast.Ident:
+				if newName, ok := dtoNames[v.Name]; ok && inPkg {
+					edits = append(edits, identEdit(fset, v, newName))
+				}
+
+			case *ast.SelectorExpr:
+				if x, ok := v.X.(*ast.Ident); ok && !inPkg && alias != "" && x.Name == alias {
+					if newName, ok := dtoNames[v.Sel.Name]; ok {
+						edits = append(edits, identEdit(fset, v.Sel, newName))
+					}
+				}
+				if renameHandler && v.Sel.Name == lowerFrom {
+					if x, ok := v.X.(*ast.Ident); ok && x.Name == "h" { // route and tracer references
+						edits = append(edits, identEdit(fset, v.Sel, lowerTo))
+					}
+				}
+				if v.Sel.Name != from {
+					return true
+				}
+				if via, ok := v.X.(*ast.SelectorExpr); ok && m.callVia != "" && via.Sel.Name == m.callVia {
+					edits = append(edits, identEdit(fset, v.Sel, to))
+				} else if m.callVia == "" && !inPkg && alias != "" {
+					unresolved = append(unresolved, fmt.Sprintf("%s:%d", path, fset.Position(v.Pos()).Line))
+				}
+			}
+			return true
+		})
+
+		// DTO names in ntaps' own comments: swagger blocks and "X generated by ntaps"
+		for _, g := range f.Comments {
+			if fix, ok := docFix[g]; ok {
+				edits = append(edits, commentEdits(fset, []*ast.CommentGroup{g}, src, fix)...)
+			} else if inPkg || alias != "" {
+				edits = append(edits, commentEdits(fset, []*ast.CommentGroup{g}, src, renameDTOs)...)
+			}
+		}
+		return edits
+	}
+
+	// validate on a first pass before writing anything
+	scan(rw)
+	if !found {
+		return nil, fmt.Errorf("method %s not found on %s in %s", from, m.recvType, m.dir)
+	}
+	if clash {
+		return nil, fmt.Errorf("%s already exists in %s", to, m.dir)
+	}
+	unresolved = nil
+
+	changed, err := rewriteAll(rw, nil)
+	if err != nil {
+		return changed, err
+	}
+	for _, u := range unresolved {
+		fmt.Printf("ℹ️  warning: %s calls .%s on something ntaps cannot resolve; rename it by hand if it is this outbound\n", u, from)
+	}
+	return changed, nil
+}
+
+// callsVia reports whether body calls <x>.<via>.<name>(...), e.g.
+// h.uc.SendUc.Submit.
+func callsVia(body *ast.BlockStmt, via, name string) bool {
+	found := false
+	if body == nil {
+		return false
+	}
+	ast.Inspect(body, func(n ast.Node) bool {
+		if sel, ok := n.(*ast.SelectorExpr); ok && sel.Sel.Name == name {
+			if x, ok := sel.X.(*ast.SelectorExpr); ok && x.Sel.Name == via {
+				found = true
+			}
+		}
+		return !found
+	})
+	return found
+}
+
+func lowerFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToLower(s[:1]) + s[1:]
+}
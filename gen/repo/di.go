@@ -7,104 +7,96 @@ import (
 	"strings"
 
 	"github.com/AndreeJait/ntaps/internal/paths"
+	"github.com/AndreeJait/ntaps/internal/tmpl"
 	"github.com/AndreeJait/ntaps/internal/util"
 )
 
 func updatePostgresDI(pkg string) error {
-	mod := util.ModulePathGuess()
 	path := paths.PgDiPath
 
-	if _, err := os.Stat(path); os.IsNotExist(err) {
-		content := fmt.Sprintf(`package db
-
-import (
-	"%s/internal/adapters/outbound/db/postgres/%s"
-	"%s/internal/infrastructure/db"
-)
-
-type Repository struct {
-	%sRepo *%s.Repository
-	TxManager *db.TxManager
-}
-`, mod, pkg, mod, util.ToPascalCase(pkg), pkg)
+	if _, err := util.Stat(path); os.IsNotExist(err) {
+		content, err := tmpl.Render("repo_di.tmpl", tmpl.RepoDI{
+			Pkg:          pkg,
+			PkgPascal:    util.ToPascalCase(pkg),
+			Import:       util.ImportPath(paths.RepoPgPath, pkg),
+			DBImport:     util.ImportPath(paths.InfraDBDir),
+			StructMarker: paths.RepositoryStructMarker,
+		})
+		if err != nil {
+			return err
+		}
 		return util.WriteGoFile(path, content)
 	}
 
-	raw, err := os.ReadFile(path)
+	raw, err := util.ReadFile(path)
 	if err != nil {
 		return err
 	}
 	src := string(raw)
 
-	impRepo := fmt.Sprintf(`"%s/internal/adapters/outbound/db/postgres/%s"`, mod, pkg)
+	impRepo := fmt.Sprintf(`"%s"`, util.ImportPath(paths.RepoPgPath, pkg))
 	if !strings.Contains(src, impRepo) {
 		src = util.InsertImport(src, impRepo)
 	}
-	impDB := fmt.Sprintf(`"%s/internal/infrastructure/db"`, mod)
+	impDB := fmt.Sprintf(`"%s"`, util.ImportPath(paths.InfraDBDir))
 	if !strings.Contains(src, impDB) {
 		src = util.InsertImport(src, impDB)
 	}
 
+	structRe := util.MarkerRegexp(paths.RepositoryStructMarker)
+	if !structRe.MatchString(src) {
+		return fmt.Errorf("%q not found in %s", paths.RepositoryStructMarker, path)
+	}
 	field := fmt.Sprintf("\t%sRepo *%s.Repository", util.ToPascalCase(pkg), pkg)
-	if !strings.Contains(src, field) {
-		src = strings.Replace(src,
-			"type Repository struct {",
-			"type Repository struct {\n\t// "+util.ToPascalCase(pkg)+"Repo\n"+field,
-			1,
-		)
+	if !util.HasField(src, util.ToPascalCase(pkg)+"Repo", "*"+pkg+".Repository") {
+		loc := structRe.FindStringIndex(src)
+		src = src[:loc[1]] + "\n\t// " + util.ToPascalCase(pkg) + "Repo\n" + field + src[loc[1]:]
 	}
-	if !strings.Contains(src, "\tTxManager *db.TxManager") {
-		src = strings.Replace(src,
-			"type Repository struct {",
-			"type Repository struct {\n\t// TxManager\n\tTxManager *db.TxManager",
-			1,
-		)
+	if !util.HasField(src, "TxManager", "*db.TxManager") {
+		loc := structRe.FindStringIndex(src)
+		src = src[:loc[1]] + "\n\t// TxManager\n\tTxManager *db.TxManager" + src[loc[1]:]
 	}
 
 	return util.WriteGoFile(path, src)
 }
 
 func updateInfraRepositoryInit(pkg string) error {
-	mod := util.ModulePathGuess()
 	path := paths.InfraRepoInitPath
 
-	raw, err := os.ReadFile(path)
+	raw, err := util.ReadFile(path)
 	if err != nil {
 		return fmt.Errorf("cannot read %s (needed to wire initRepository): %w", path, err)
 	}
 	src := string(raw)
 
-	impRepo := fmt.Sprintf(`"%s/internal/adapters/outbound/db/postgres/%s"`, mod, pkg)
+	impRepo := fmt.Sprintf(`"%s"`, util.ImportPath(paths.RepoPgPath, pkg))
 	if !strings.Contains(src, impRepo) {
 		src = util.InsertImport(src, impRepo)
 	}
-	impDB := fmt.Sprintf(`"%s/internal/infrastructure/db"`, mod)
+	impDB := fmt.Sprintf(`"%s"`, util.ImportPath(paths.InfraDBDir))
 	if !strings.Contains(src, impDB) {
 		src = util.InsertImport(src, impDB)
 	}
 
-	if !strings.Contains(src, "func (s wire) initRepository(") &&
-		!strings.Contains(src, "func (s wire) initRepository()") {
-		src += `
-
-func (s wire) initRepository() {
-	// generated by ntaps
-}
-`
+	re := util.MarkerRegexp(paths.RepositoryInitMarker)
+	if !re.MatchString(src) {
+		src += "\n\n" + paths.RepositoryInitMarker + ") {\n\t// generated by ntaps\n}\n"
 	}
 
 	assignRepo := fmt.Sprintf(
-		`s.repo.%sRepo = %s.New%sRepository(s.pg)`,
-		util.ToPascalCase(pkg), pkg, util.ToPascalCase(pkg),
+		`%[1]s.repo.%[2]sRepo = %[3]s.New%[2]sRepository(%[1]s.pg)`,
+		paths.WireReceiver, util.ToPascalCase(pkg), pkg,
 	)
-	assignTx := `s.repo.TxManager = db.NewTxManager(s.pg)`
+	assignTx := fmt.Sprintf(`%[1]s.repo.TxManager = db.NewTxManager(%[1]s.pg)`, paths.WireReceiver)
 
-	re := regexp.MustCompile(`func \(s\s+wire\)\s+initRepository\([^\)]*\)\s*\{`)
 	loc := re.FindStringIndex(src)
 	if loc == nil {
-		return fmt.Errorf("initRepository() not found in %s", path)
+		return fmt.Errorf("%q not found in %s", paths.RepositoryInitMarker, path)
+	}
+	insertAt := util.FuncBodyStart(src, loc[1])
+	if insertAt == -1 {
+		return fmt.Errorf("body of %q not found in %s", paths.RepositoryInitMarker, path)
 	}
-	insertAt := loc[1]
 
 	if !strings.Contains(src, assignRepo) {
 		src = src[:insertAt] + "\n\t// generated by ntaps\n\t" + assignRepo + src[insertAt:]
@@ -128,22 +120,21 @@ func ensureUsecaseRepoInterface(
 	ucPkg, repoPkg, method string,
 	withParam, withResp, withTx bool,
 ) error {
-	mod := util.ModulePathGuess()
 	path := fmt.Sprintf("%s/%s/port.go", paths.RootUsecaseDir, ucPkg)
 
-	if _, err := os.Stat(path); os.IsNotExist(err) {
+	if _, err := util.Stat(path); os.IsNotExist(err) {
 		if err := util.WriteGoFile(path, "package "+ucPkg+"\n\nimport \"context\"\n\ntype UseCase interface{}\n"); err != nil {
 			return err
 		}
 	}
 
-	raw, err := os.ReadFile(path)
+	raw, err := util.ReadFile(path)
 	if err != nil {
 		return err
 	}
 	src := string(raw)
 
-	impRepo := fmt.Sprintf(`"%s/internal/adapters/outbound/db/postgres/%s"`, mod, repoPkg)
+	impRepo := fmt.Sprintf(`"%s"`, util.ImportPath(paths.RepoPgPath, repoPkg))
 	if !strings.Contains(src, impRepo) {
 		src = util.InsertImport(src, impRepo)
 	}
@@ -197,7 +188,7 @@ func ensureUsecaseRepoInterface(
 
 func ensureUsecaseHasRepo(ucPkg, repoPkg string) error {
 	path := fmt.Sprintf("%s/%s/usecase.go", paths.RootUsecaseDir, ucPkg)
-	raw, err := os.ReadFile(path)
+	raw, err := util.ReadFile(path)
 	if err != nil {
 		return err
 	}
@@ -210,7 +201,7 @@ func ensureUsecaseHasRepo(ucPkg, repoPkg string) error {
 	fieldName := util.ToCamelCase(repoPkg) + "Repo"
 
 	// ensure struct field
-	if strings.Contains(src, "type useCase struct") && !strings.Contains(src, fieldName+" "+iface) {
+	if strings.Contains(src, "type useCase struct") && !util.HasField(src, fieldName, iface) {
 		src = strings.Replace(src,
 			"type useCase struct {",
 			"type useCase struct {\n\t// ntaps:generated\n\t"+fieldName+" "+iface,
@@ -250,17 +241,17 @@ func ensureUsecaseHasRepo(ucPkg, repoPkg string) error {
 func updateInfraUsecaseInitArgs(ucPkg, repoPkg string) error {
 	path := paths.InfraInitUsecasePath
 
-	raw, err := os.ReadFile(path)
+	raw, err := util.ReadFile(path)
 	if err != nil {
 		return fmt.Errorf("read %s: %w", path, err)
 	}
 	src := string(raw)
 
 	ucField := util.ToPascalCase(ucPkg) + "Uc"
-	repoArg := "s.repo." + util.ToPascalCase(repoPkg) + "Repo"
+	repoArg := paths.WireReceiver + ".repo." + util.ToPascalCase(repoPkg) + "Repo"
 
 	re := regexp.MustCompile(
-		fmt.Sprintf(`s\.uc\.%s\s*=\s*%s\.NewUseCase\(([\s\S]*?)\)`, ucField, ucPkg),
+		fmt.Sprintf(`%s\.uc\.%s\s*=\s*%s\.NewUseCase\(([\s\S]*?)\)`, regexp.QuoteMeta(paths.WireReceiver), ucField, ucPkg),
 	)
 	m := re.FindStringSubmatchIndex(src)
 	if m == nil {
@@ -0,0 +1,201 @@
+package repo
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/AndreeJait/ntaps/internal/paths"
+	"github.com/AndreeJait/ntaps/internal/tmpl"
+	"github.com/AndreeJait/ntaps/internal/util"
+)
+
+// RunFromQuery is Run with the method derived from the sqlc query of the
+// same name: DTO fields mirror the sqlc params/row and the body calls it.
+func RunFromQuery(pkg, query string, withTx bool, addToUC string) error {
+	s, err := loadSqlc()
+	if err != nil {
+		return err
+	}
+	return runFromQuery(s, pkg, query, withTx, addToUC)
+}
+
+// RunFromSqlc runs RunFromQuery for every sqlc query.
+func RunFromSqlc(pkg string, withTx bool, addToUC string) ([]string, error) {
+	s, err := loadSqlc()
+	if err != nil {
+		return nil, err
+	}
+	names := s.names()
+	for _, q := range names {
+		fmt.Printf("• repository %s.%s\n", pkg, q)
+		if err := runFromQuery(s, pkg, q, withTx, addToUC); err != nil {
+			return nil, err
+		}
+	}
+	return names, nil
+}
+
+func runFromQuery(s *sqlcPkg, pkg, query string, withTx bool, addToUC string) error {
+	q, err := s.query(query)
+	if err != nil {
+		return err
+	}
+	method := query
+	withParam, withResp := q.argType != "", q.kind != "exec"
+
+	if err := ensureRepoPkgPostgres(pkg); err != nil {
+		return err
+	}
+
+	data := tmpl.RepoSqlc{
+		Repo:     templateData(pkg, method, withParam, withResp, withTx),
+		Query:    query,
+		Kind:     q.kind,
+		ItemType: method + "Item",
+	}
+
+	dtos := map[string][]util.StructField{}
+	var order []string
+	add := func(name string, fields []util.StructField) {
+		order = append(order, name)
+		dtos[name] = fields
+	}
+	if withParam {
+		if q.argSt != nil {
+			fields := q.fields(q.argSt)
+			add(method+"Param", fields)
+			data.ArgType, data.ArgFields = q.argType, fieldNames(fields)
+		} else {
+			data.ArgField = util.ExportedName(q.argName)
+			add(method+"Param", []util.StructField{{Name: data.ArgField, Type: q.argType}})
+		}
+	}
+	var row []util.StructField
+	if q.rowSt != nil {
+		row = q.fields(q.rowSt)
+		data.RowFields = fieldNames(row)
+	}
+	switch q.kind {
+	case "one":
+		if row == nil {
+			row = []util.StructField{{Name: "Value", Type: q.rowType}}
+		}
+		add(method+"Response", row)
+	case "many":
+		if row == nil {
+			add(method+"Response", []util.StructField{{Name: "Items", Type: "[]" + q.rowType}})
+			data.ItemType = q.rowType
+			break
+		}
+		add(method+"Item", row)
+		add(method+"Response", []util.StructField{{Name: "Items", Type: "[]" + method + "Item"}})
+	case "execrows", "execresult":
+		add(method+"Response", []util.StructField{{Name: "RowsAffected", Type: "int64"}})
+	}
+	if err := fillRepoDTOs(pkg, order, dtos, q.imports); err != nil {
+		return err
+	}
+
+	if err := ensureSqlcRepoMethod(pkg, data); err != nil {
+		return err
+	}
+	return wireRepo(pkg, method, withParam, withResp, withTx, addToUC)
+}
+
+// fillRepoDTOs writes every type in one go so the imports the sqlc field
+// types need are present when the file is formatted.
+func fillRepoDTOs(pkg string, order []string, dtos map[string][]util.StructField, imports map[string]bool) error {
+	path := filepath.Join(paths.RepoPgPath, pkg, "dto.go")
+	src := "package " + pkg + "\n\n"
+	if raw, err := util.ReadFile(path); err == nil {
+		src = string(raw)
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+
+	for _, name := range order {
+		if !strings.Contains(src, "type "+name+" struct") {
+			s, err := tmpl.Render("dto_struct.tmpl", tmpl.DTO{Pkg: pkg, Name: name, Comment: "generated by ntaps"})
+			if err != nil {
+				return err
+			}
+			src += s
+		}
+		var err error
+		if src, err = util.EnsureStructFields(src, name, dtos[name]); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+	}
+	for imp := range imports {
+		src = util.InsertImport(src, `"`+imp+`"`)
+	}
+	return util.WriteGoFile(path, src)
+}
+
+func ensureSqlcRepoMethod(pkg string, data tmpl.RepoSqlc) error {
+	path := filepath.Join(paths.RepoPgPath, pkg, "impl.go")
+
+	raw, err := util.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	src := string(raw)
+
+	if strings.Contains(src, fmt.Sprintf("func (r *Repository) %s(", data.Method)) {
+		if !isScaffold(src, data.Method) {
+			fmt.Printf("ℹ️  %s.%s already exists, leaving its body alone\n", pkg, data.Method)
+			return nil
+		}
+		// still the `_ = q` placeholder from create-repository: replace it
+		src, _ = util.RemoveFunc(src, "Repository", data.Method)
+	}
+
+	reqImports := []string{
+		`"context"`,
+		fmt.Sprintf(`"%s"`, data.SqlcImport),
+		`"github.com/AndreeJait/go-utility/tracer"`,
+	}
+	if data.WithTx || data.Kind == "one" {
+		reqImports = append(reqImports, `"github.com/jackc/pgx/v5"`)
+	}
+	if data.Kind == "one" {
+		reqImports = append(reqImports, `"errors"`, `"fmt"`)
+		if !strings.Contains(src, "ErrNotFound =") {
+			src += "\n// ErrNotFound is returned (wrapping pgx.ErrNoRows) when a :one query matches no row.\n" +
+				fmt.Sprintf("var ErrNotFound = errors.New(%q)\n", pkg+": not found")
+		}
+	}
+	for _, imp := range reqImports {
+		src = util.InsertImport(src, imp)
+	}
+
+	methodCode, err := tmpl.Render("repo_method_sqlc.tmpl", data)
+	if err != nil {
+		return err
+	}
+	return util.WriteGoFile(path, src+methodCode)
+}
+
+// isScaffold reports whether method's body is still repo_method.tmpl's
+// placeholder.
+func isScaffold(src, method string) bool {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "", src, 0)
+	if err != nil {
+		return false
+	}
+	for _, d := range f.Decls {
+		fd, ok := d.(*ast.FuncDecl)
+		if !ok || fd.Recv == nil || fd.Name.Name != method || fd.Body == nil {
+			continue
+		}
+		body := src[fset.Position(fd.Body.Pos()).Offset:fset.Position(fd.Body.End()).Offset]
+		return strings.Contains(body, "// TODO: implement") && strings.Contains(body, "_ = q")
+	}
+	return false
+}
@@ -2,20 +2,19 @@ package repo
 
 import (
 	"fmt"
-	"os"
 	"path/filepath"
 
 	"strings"
 
 	"github.com/AndreeJait/ntaps/internal/paths"
+	"github.com/AndreeJait/ntaps/internal/tmpl"
 	"github.com/AndreeJait/ntaps/internal/util"
 )
 
 func ensureRepoMethod(pkg, method string, withParam, withResp, withTx bool) error {
-	mod := util.ModulePathGuess()
 	path := filepath.Join(paths.RepoPgPath, pkg, "impl.go")
 
-	raw, err := os.ReadFile(path)
+	raw, err := util.ReadFile(path)
 	if err != nil {
 		return err
 	}
@@ -24,7 +23,7 @@ func ensureRepoMethod(pkg, method string, withParam, withResp, withTx bool) erro
 	// required imports
 	reqImports := []string{
 		`"context"`,
-		fmt.Sprintf(`"%s/internal/adapters/outbound/db/postgres/sqlc"`, mod),
+		fmt.Sprintf(`"%s"`, util.ImportPath(paths.SqlcDir)),
 		`"github.com/AndreeJait/go-utility/tracer"`,
 	}
 	if withTx {
@@ -40,7 +39,31 @@ func ensureRepoMethod(pkg, method string, withParam, withResp, withTx bool) erro
 		return util.WriteGoFile(path, src)
 	}
 
-	// signature
+	methodCode, err := tmpl.Render("repo_method.tmpl", templateData(pkg, method, withParam, withResp, withTx))
+	if err != nil {
+		return err
+	}
+
+	src += methodCode
+	return util.WriteGoFile(path, src)
+}
+
+// templateData builds what repo_*.tmpl receives.
+func templateData(pkg, method string, withParam, withResp, withTx bool) tmpl.Repo {
+	return tmpl.Repo{
+		Pkg:          pkg,
+		PkgPascal:    util.ToPascalCase(pkg),
+		Method:       method,
+		WithParam:    withParam,
+		WithResponse: withResp,
+		WithTx:       withTx,
+		Signature:    signature(method, withParam, withResp, withTx),
+		SqlcImport:   util.ImportPath(paths.SqlcDir),
+	}
+}
+
+// signature renders the repo method signature as seen inside the repo pkg.
+func signature(method string, withParam, withResp, withTx bool) string {
 	args := "ctx context.Context"
 	if withParam {
 		args += fmt.Sprintf(", param %sParam", method)
@@ -48,35 +71,9 @@ func ensureRepoMethod(pkg, method string, withParam, withResp, withTx bool) erro
 	if withTx {
 		args += ", tx pgx.Tx"
 	}
-
 	ret := "error"
-	retBody := "\t// TODO: implement\n\t_ = q\n\treturn nil"
 	if withResp {
 		ret = fmt.Sprintf("(%sResponse, error)", method)
-		retBody = fmt.Sprintf("\tvar resp %sResponse\n\t// TODO: implement\n\t_ = q\n\treturn resp, nil", method)
-	}
-
-	// q := r.q / tx override logic
-	qSetup := "\n\tq := r.q\n"
-	if withTx {
-		qSetup = `
-	q := r.q
-	if tx != nil {
-		q = sqlc.New(tx)
-	}
-`
 	}
-
-	methodCode := fmt.Sprintf(`
-
-func (r *Repository) %s(%s) %s {
-	span, ctx := tracer.StartSpan(ctx, tracer.GetFuncName(r.%s))
-	defer span.End()
-	%s
-%s
-}
-`, method, args, ret, method, strings.TrimRight(qSetup, "\n"), retBody)
-
-	src += methodCode
-	return util.WriteGoFile(path, src)
+	return fmt.Sprintf("%s(%s) %s", method, args, ret)
 }
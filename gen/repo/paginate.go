@@ -0,0 +1,36 @@
+package repo
+
+import (
+	"fmt"
+
+	"github.com/AndreeJait/ntaps/internal/util"
+)
+
+// Paginate adds a <method> repository method reading one page: limit and
+// offset (plus the total), or limit and the keyset after_id. The rows are
+// <method>Row, created with a TODO on first use. addToUC wires it into
+// that usecase.
+func Paginate(pkg, method, style, addToUC string) error {
+	param := []util.StructField{{Name: "Limit", Type: "int32", Tag: `db:"limit"`}}
+	resp := []util.StructField{{Name: "Items", Type: "[]" + method + "Row"}}
+	switch style {
+	case "offset":
+		param = append(param, util.StructField{Name: "Offset", Type: "int32", Tag: `db:"offset"`})
+		resp = append(resp, util.StructField{Name: "Total", Type: "int64"})
+	case "cursor":
+		param = append(param, util.StructField{Name: "AfterID", Type: "int64", Tag: `db:"after_id"`})
+	default:
+		return fmt.Errorf("pagination %q: use offset or cursor", style)
+	}
+
+	if err := Run(pkg, method, true, true, false, addToUC); err != nil {
+		return err
+	}
+	if err := AddFields(pkg, method+"Row", nil); err != nil {
+		return err
+	}
+	if err := AddFields(pkg, method+"Param", param); err != nil {
+		return err
+	}
+	return AddFields(pkg, method+"Response", resp)
+}
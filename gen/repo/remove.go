@@ -0,0 +1,145 @@
+package repo
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/AndreeJait/ntaps/internal/paths"
+	"github.com/AndreeJait/ntaps/internal/util"
+)
+
+// Remove reverses create-repository for a whole package: the package dir,
+// its field in the aggregated Repository struct and its assignment in
+// initRepository. It refuses while a usecase still has it injected.
+func Remove(pkg string, force bool) error {
+	dir := filepath.Join(paths.RepoPgPath, pkg)
+	if _, err := util.Stat(dir); os.IsNotExist(err) {
+		return fmt.Errorf("repository %q not found in %s", pkg, paths.RepoPgPath)
+	}
+
+	field := util.ToPascalCase(pkg) + "Repo"
+	refs := util.FindReferences(regexp.MustCompile(fmt.Sprintf(
+		`"%s"|\.repo\.%s\b`, regexp.QuoteMeta(util.ImportPath(paths.RepoPgPath, pkg)), field,
+	)), dir, paths.PgDiPath, paths.InfraRepoInitPath)
+	if err := util.GuardReferences("repository "+pkg, refs, force); err != nil {
+		return err
+	}
+
+	if raw, err := util.ReadFile(paths.PgDiPath); err == nil {
+		if src, ok := util.RemoveStructField(string(raw), "Repository", field); ok {
+			if err := util.WriteGoFile(paths.PgDiPath, src); err != nil {
+				return err
+			}
+		}
+	}
+
+	if raw, err := util.ReadFile(paths.InfraRepoInitPath); err == nil {
+		// updateInfraRepositoryInit puts a "// generated by ntaps" line right
+		// above each assignment; drop it together with the assignment.
+		assignRe := regexp.MustCompile(fmt.Sprintf(
+			`(?m)^[ \t]*(// generated by ntaps\n[ \t]*)?%s\.repo\.%s\s*=.*\n`, regexp.QuoteMeta(paths.WireReceiver), field,
+		))
+		src := assignRe.ReplaceAllString(string(raw), "")
+		if src != string(raw) {
+			if err := util.WriteGoFile(paths.InfraRepoInitPath, src); err != nil {
+				return err
+			}
+		}
+	}
+
+	return util.RemoveAll(dir)
+}
+
+// RemoveFromUsecase reverses AddRepoToUsecase: the <Repo> interface in the
+// usecase port, the useCase field, the NewUseCase parameter and its
+// argument in initUseCase. It refuses while usecase methods still use it.
+func RemoveFromUsecase(repoPkg, ucPkg string, force bool) error {
+	dir := filepath.Join(paths.RootUsecaseDir, ucPkg)
+	portPath := filepath.Join(dir, "port.go")
+	implPath := filepath.Join(dir, "usecase.go")
+
+	iface := "Repo"
+	if repoPkg != ucPkg {
+		iface = util.ToPascalCase(repoPkg) + "Repo"
+	}
+	fieldName := util.ToCamelCase(repoPkg) + "Repo"
+
+	rawPort, err := util.ReadFile(portPath)
+	if err != nil {
+		return fmt.Errorf("usecase %q not found: %w", ucPkg, err)
+	}
+	port, portOK := util.RemoveType(string(rawPort), iface)
+
+	rawImpl, err := util.ReadFile(implPath)
+	if err != nil {
+		return err
+	}
+	impl, implOK := util.RemoveStructField(string(rawImpl), "useCase", fieldName)
+	if !portOK && !implOK {
+		return fmt.Errorf("repository %s is not injected into usecase %s", repoPkg, ucPkg)
+	}
+	impl = regexp.MustCompile(`,\s*`+fieldName+`\s+`+iface+`\b`).ReplaceAllString(impl, "")
+	impl = regexp.MustCompile(`\b`+fieldName+`\s+`+iface+`\s*,\s*`).ReplaceAllString(impl, "")
+	impl = regexp.MustCompile(`\b`+fieldName+`:\s*`+fieldName+`\s*,\s*`).ReplaceAllString(impl, "")
+	impl = regexp.MustCompile(`,\s*`+fieldName+`:\s*`+fieldName+`\b`).ReplaceAllString(impl, "")
+
+	// whatever still mentions the field or interface is user code
+	useRe := regexp.MustCompile(`\.` + fieldName + `\b|\b` + iface + `\b`)
+	refs := append(util.MatchLines(portPath, port, useRe), util.MatchLines(implPath, impl, useRe)...)
+	files, err := util.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return err
+	}
+	for _, p := range files {
+		if p == portPath || p == implPath {
+			continue
+		}
+		if b, err := util.ReadFile(p); err == nil {
+			refs = append(refs, util.MatchLines(p, string(b), useRe)...)
+		}
+	}
+	if err := util.GuardReferences(fmt.Sprintf("%s in usecase %s", fieldName, ucPkg), refs, force); err != nil {
+		return err
+	}
+
+	if err := util.WriteGoFile(portPath, port); err != nil {
+		return err
+	}
+	if err := util.WriteGoFile(implPath, impl); err != nil {
+		return err
+	}
+	return removeInfraUsecaseInitArg(ucPkg, repoPkg)
+}
+
+// removeInfraUsecaseInitArg is the inverse of updateInfraUsecaseInitArgs.
+func removeInfraUsecaseInitArg(ucPkg, repoPkg string) error {
+	path := paths.InfraInitUsecasePath
+
+	raw, err := util.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("read %s: %w", path, err)
+	}
+	src := string(raw)
+
+	re := regexp.MustCompile(fmt.Sprintf(
+		`%s\.uc\.%sUc\s*=\s*%s\.NewUseCase\(([\s\S]*?)\)`,
+		regexp.QuoteMeta(paths.WireReceiver), util.ToPascalCase(ucPkg), ucPkg,
+	))
+	m := re.FindStringSubmatchIndex(src)
+	if m == nil {
+		return nil
+	}
+
+	repoArg := paths.WireReceiver + ".repo." + util.ToPascalCase(repoPkg) + "Repo"
+	var kept []string
+	for _, a := range strings.Split(src[m[2]:m[3]], ",") {
+		if a = strings.TrimSpace(a); a != "" && a != repoArg {
+			kept = append(kept, a)
+		}
+	}
+	src = src[:m[2]] + strings.Join(kept, ", ") + src[m[3]:]
+	return util.WriteGoFile(path, src)
+}
@@ -12,6 +12,7 @@ import (
 
 	"github.com/AndreeJait/ntaps/gen/usecase"
 	"github.com/AndreeJait/ntaps/internal/paths"
+	"github.com/AndreeJait/ntaps/internal/util"
 )
 
 func Run(pkg, method string, withParamRepo, withRespRepo, withTx bool, addToUC string) error {
@@ -28,20 +29,25 @@ func Run(pkg, method string, withParamRepo, withRespRepo, withTx bool, addToUC s
 		return err
 	}
 
-	// 3. update postgres di.go
+	return wireRepo(pkg, method, withParamRepo, withRespRepo, withTx, addToUC)
+}
+
+// wireRepo registers the repo in DI and optionally wires it into a usecase.
+func wireRepo(pkg, method string, withParamRepo, withRespRepo, withTx bool, addToUC string) error {
+	// 1. update postgres di.go
 	if err := updatePostgresDI(pkg); err != nil {
 		return err
 	}
 
-	// 4. wire initRepository() infra
+	// 2. wire initRepository() infra
 	if err := updateInfraRepositoryInit(pkg); err != nil {
 		return err
 	}
 
-	// 5. optionally wire into usecase (repo interface, struct field, ctor, init args)
+	// 3. optionally wire into usecase (repo interface, struct field, ctor, init args)
 	if addToUC != "" {
 		ucDir := filepath.Join(paths.RootUsecaseDir, addToUC)
-		if _, err := os.Stat(ucDir); errors.Is(err, os.ErrNotExist) {
+		if _, err := util.Stat(ucDir); errors.Is(err, os.ErrNotExist) {
 			fmt.Println("ℹ️  repo not added to usecase because usecase is not found:", addToUC)
 			return nil
 		}
@@ -71,7 +77,7 @@ func Run(pkg, method string, withParamRepo, withRespRepo, withTx bool, addToUC s
 func AddRepoToUsecase(repoPkg, ucPkg, method string, withParamRepo, withRespRepo, withTx bool) error {
 	// Sanity: does the ucPkg actually exist?
 	ucDir := filepath.Join(paths.RootUsecaseDir, ucPkg)
-	if _, err := os.Stat(ucDir); errors.Is(err, os.ErrNotExist) {
+	if _, err := util.Stat(ucDir); errors.Is(err, os.ErrNotExist) {
 		return fmt.Errorf("usecase package %q not found under %s", ucPkg, ucDir)
 	}
 
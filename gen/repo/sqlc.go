@@ -0,0 +1,261 @@
+package repo
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/AndreeJait/ntaps/internal/paths"
+	"github.com/AndreeJait/ntaps/internal/util"
+)
+
+// sqlcQueryNameRe matches sqlc's annotation inside the generated query
+// constants: `-- name: GetUserByID :one`.
+var sqlcQueryNameRe = regexp.MustCompile(`--\s*name:\s*(\w+)\s+:(\w+)`)
+
+// sqlcPkg is what create-repository --fromQuery needs from the generated
+// sqlc package: query signatures, the structs they use and their imports.
+type sqlcPkg struct {
+	queries map[string]*ast.FuncType   // Querier (or *Queries) methods
+	kinds   map[string]string          // query -> one|many|exec|...
+	structs map[string]*ast.StructType // type name -> struct
+	imports map[string]string          // qualifier -> import path
+}
+
+// sqlcQuery is one query mapped onto a repo method.
+type sqlcQuery struct {
+	name    string
+	kind    string
+	argName string // scalar argument name, e.g. id
+	argType string // Go type as seen from the repo pkg, e.g. int64 or sqlc.CreateUserParams
+	argSt   *ast.StructType
+	rowType string // sqlc.User, int64, ...
+	rowSt   *ast.StructType
+	imports map[string]bool
+	sqlc    *sqlcPkg
+}
+
+func loadSqlc() (*sqlcPkg, error) {
+	files, err := util.Glob(filepath.Join(paths.SqlcDir, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no sqlc code in %s (run `sqlc generate` first)", paths.SqlcDir)
+	}
+
+	p := &sqlcPkg{
+		queries: map[string]*ast.FuncType{},
+		kinds:   map[string]string{},
+		structs: map[string]*ast.StructType{},
+		imports: map[string]string{},
+	}
+	var querier map[string]*ast.FuncType
+	fset := token.NewFileSet()
+	for _, file := range files {
+		raw, err := util.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		f, err := parser.ParseFile(fset, file, raw, 0)
+		if err != nil {
+			return nil, fmt.Errorf("parse %s: %w", file, err)
+		}
+		for _, imp := range f.Imports {
+			path, _ := strconv.Unquote(imp.Path.Value)
+			name := filepath.Base(path)
+			if v := filepath.Base(path); strings.HasPrefix(v, "v") && strings.Trim(v[1:], "0123456789") == "" {
+				name = filepath.Base(filepath.Dir(path)) // github.com/jackc/pgx/v5 -> pgx
+			}
+			if imp.Name != nil {
+				name = imp.Name.Name
+			}
+			p.imports[name] = path
+		}
+		for _, m := range sqlcQueryNameRe.FindAllStringSubmatch(string(raw), -1) {
+			p.kinds[m[1]] = m[2]
+		}
+		for _, decl := range f.Decls {
+			switch d := decl.(type) {
+			case *ast.GenDecl:
+				for _, spec := range d.Specs {
+					ts, ok := spec.(*ast.TypeSpec)
+					if !ok {
+						continue
+					}
+					switch t := ts.Type.(type) {
+					case *ast.StructType:
+						p.structs[ts.Name.Name] = t
+					case *ast.InterfaceType:
+						if ts.Name.Name != "Querier" {
+							continue
+						}
+						querier = map[string]*ast.FuncType{}
+						for _, m := range t.Methods.List {
+							if ft, ok := m.Type.(*ast.FuncType); ok && len(m.Names) == 1 {
+								querier[m.Names[0].Name] = ft
+							}
+						}
+					}
+				}
+			case *ast.FuncDecl:
+				if d.Recv != nil && len(d.Recv.List) == 1 && types.ExprString(d.Recv.List[0].Type) == "*Queries" && d.Name.Name != "WithTx" {
+					p.queries[d.Name.Name] = d.Type
+				}
+			}
+		}
+	}
+	if querier != nil { // emit_interface: the Querier is the contract
+		p.queries = querier
+	}
+	return p, nil
+}
+
+// names lists every query, sorted.
+func (p *sqlcPkg) names() []string {
+	var out []string
+	for n := range p.queries {
+		out = append(out, n)
+	}
+	sort.Strings(out)
+	return out
+}
+
+func (p *sqlcPkg) query(name string) (*sqlcQuery, error) {
+	ft, ok := p.queries[name]
+	if !ok {
+		return nil, fmt.Errorf("sqlc query %s not found in %s (have: %s)", name, paths.SqlcDir, strings.Join(p.names(), ", "))
+	}
+	q := &sqlcQuery{name: name, imports: map[string]bool{}, sqlc: p}
+
+	params := ft.Params.List
+	var args []*ast.Field
+	for _, f := range params {
+		if types.ExprString(f.Type) == "context.Context" {
+			continue
+		}
+		args = append(args, f)
+	}
+	switch {
+	case len(args) > 1 || len(args) == 1 && len(args[0].Names) > 1:
+		return nil, fmt.Errorf("sqlc query %s: more than one argument is not supported", name)
+	case len(args) == 1:
+		q.argType = q.qualify(args[0].Type)
+		if len(args[0].Names) == 1 {
+			q.argName = args[0].Names[0].Name
+		}
+		if id, ok := args[0].Type.(*ast.Ident); ok {
+			q.argSt = p.structs[id.Name]
+		}
+	}
+
+	var results []ast.Expr
+	if ft.Results != nil {
+		for _, f := range ft.Results.List {
+			results = append(results, f.Type)
+		}
+	}
+	if n := len(results); n == 0 || types.ExprString(results[n-1]) != "error" {
+		return nil, fmt.Errorf("sqlc query %s: unexpected signature", name)
+	}
+	results = results[:len(results)-1]
+
+	q.kind = p.kinds[name]
+	if q.kind == "" { // no annotation found; infer from the signature
+		switch {
+		case len(results) == 0:
+			q.kind = "exec"
+		case types.ExprString(results[0]) == "pgconn.CommandTag":
+			q.kind = "execresult"
+		default:
+			if _, ok := results[0].(*ast.ArrayType); ok {
+				q.kind = "many"
+			} else {
+				q.kind = "one"
+			}
+		}
+	}
+	switch q.kind {
+	case "exec":
+	case "one", "many", "execrows", "execresult":
+		if len(results) != 1 {
+			return nil, fmt.Errorf("sqlc query %s: unexpected signature", name)
+		}
+		row := results[0]
+		if at, ok := row.(*ast.ArrayType); ok && q.kind == "many" {
+			row = at.Elt
+		}
+		q.rowType = q.qualify(row)
+		if st, ok := row.(*ast.StarExpr); ok { // emit_result_struct_pointers
+			row = st.X
+		}
+		if id, ok := row.(*ast.Ident); ok {
+			q.rowSt = p.structs[id.Name]
+		}
+	default:
+		return nil, fmt.Errorf("sqlc query %s: :%s queries are not supported", name, q.kind)
+	}
+	return q, nil
+}
+
+// qualify renders a type from the sqlc package as seen from the repo
+// package, recording the imports it needs.
+func (q *sqlcQuery) qualify(e ast.Expr) string {
+	switch t := e.(type) {
+	case *ast.Ident:
+		if types.Universe.Lookup(t.Name) != nil {
+			return t.Name
+		}
+		q.imports[util.ImportPath(paths.SqlcDir)] = true
+		return "sqlc." + t.Name
+	case *ast.SelectorExpr:
+		if x, ok := t.X.(*ast.Ident); ok {
+			if path, ok := q.sqlc.imports[x.Name]; ok {
+				q.imports[path] = true
+			}
+		}
+		return types.ExprString(t)
+	case *ast.StarExpr:
+		return "*" + q.qualify(t.X)
+	case *ast.ArrayType:
+		return "[]" + q.qualify(t.Elt)
+	case *ast.MapType:
+		return "map[" + q.qualify(t.Key) + "]" + q.qualify(t.Value)
+	}
+	return types.ExprString(e)
+}
+
+// fields mirrors a sqlc struct as DTO fields (same names, types and tags).
+func (q *sqlcQuery) fields(st *ast.StructType) []util.StructField {
+	var out []util.StructField
+	for _, f := range st.Fields.List {
+		typ := q.qualify(f.Type)
+		tag := ""
+		if f.Tag != nil {
+			tag = strings.Trim(f.Tag.Value, "`")
+		}
+		names := f.Names
+		if len(names) == 0 { // sqlc.embed
+			names = []*ast.Ident{ast.NewIdent(strings.TrimPrefix(typ[strings.LastIndex(typ, ".")+1:], "*"))}
+		}
+		for _, n := range names {
+			out = append(out, util.StructField{Name: n.Name, Type: typ, Tag: tag})
+		}
+	}
+	return out
+}
+
+func fieldNames(fields []util.StructField) []string {
+	out := make([]string, 0, len(fields))
+	for _, f := range fields {
+		out = append(out, f.Name)
+	}
+	return out
+}
@@ -0,0 +1,163 @@
+package repo
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/AndreeJait/ntaps/internal/paths"
+	"github.com/AndreeJait/ntaps/internal/tmpl"
+	"github.com/AndreeJait/ntaps/internal/util"
+)
+
+// QueryKind is the sqlc annotation matching a repo method's shape.
+func QueryKind(withResp, many bool) string {
+	switch {
+	case withResp && many:
+		return "many"
+	case withResp:
+		return "one"
+	default:
+		return "exec"
+	}
+}
+
+// EnsureQueryStub appends a `-- name: <method> :<kind>` stub to
+// <queriesDir>/<pkg>.sql and makes sure sqlc.yaml lists that file. It
+// returns the query file path.
+func EnsureQueryStub(pkg, method, kind string) (string, error) {
+	path := filepath.ToSlash(filepath.Join(paths.QueriesDir, pkg+".sql"))
+
+	src := ""
+	if raw, err := util.ReadFile(path); err == nil {
+		src = string(raw)
+	} else if !os.IsNotExist(err) {
+		return "", err
+	}
+
+	nameRe := regexp.MustCompile(`(?m)^--\s*name:\s*` + regexp.QuoteMeta(method) + `\s`)
+	if !nameRe.MatchString(src) {
+		stub, err := tmpl.Render("sql_query_stub.tmpl", tmpl.QueryStub{Pkg: pkg, Method: method, Kind: kind})
+		if err != nil {
+			return "", err
+		}
+		if src == "" {
+			stub = strings.TrimLeft(stub, "\n")
+		} else if !strings.HasSuffix(src, "\n") {
+			src += "\n"
+		}
+		if err := util.WriteFile(path, src+stub); err != nil {
+			return "", err
+		}
+	}
+
+	return path, ensureSqlcConfig(path)
+}
+
+// ensureSqlcConfig creates sqlc.yaml, or adds file to the queries of the
+// entry generating into paths.SqlcDir (the first entry if none matches).
+// A new sqlc.yaml points schema at paths.MigrationsDir, which is created
+// when missing since sqlc refuses a schema path that does not exist.
+func ensureSqlcConfig(file string) error {
+	raw, err := util.ReadFile(paths.SqlcConfigPath)
+	if os.IsNotExist(err) {
+		if _, err := util.Stat(paths.MigrationsDir); os.IsNotExist(err) {
+			if err := util.MkdirAll(paths.MigrationsDir); err != nil {
+				return err
+			}
+			fmt.Printf("ℹ️  created %s for the sqlc schema; add the table migrations there (ntaps create-migration)\n", paths.MigrationsDir)
+		}
+		out, err := tmpl.Render("sqlc_yaml.tmpl", tmpl.SqlcConfig{
+			Queries: file,
+			Schema:  paths.MigrationsDir,
+			Out:     paths.SqlcDir,
+		})
+		if err != nil {
+			return err
+		}
+		return util.WriteFile(paths.SqlcConfigPath, out)
+	}
+	if err != nil {
+		return err
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(raw, &doc); err != nil {
+		return fmt.Errorf("parse %s: %w", paths.SqlcConfigPath, err)
+	}
+	entry := sqlcEntry(&doc)
+	if entry == nil {
+		return fmt.Errorf("%s: no `sql:` entry to add %s to", paths.SqlcConfigPath, file)
+	}
+
+	queries := mapValue(entry, "queries")
+	switch {
+	case queries == nil:
+		entry.Content = append(entry.Content,
+			&yaml.Node{Kind: yaml.ScalarNode, Value: "queries"},
+			&yaml.Node{Kind: yaml.SequenceNode, Content: []*yaml.Node{{Kind: yaml.ScalarNode, Value: file}}})
+	case queries.Kind == yaml.ScalarNode:
+		if covers(queries.Value, file) {
+			return nil
+		}
+		old := *queries
+		*queries = yaml.Node{Kind: yaml.SequenceNode, Content: []*yaml.Node{&old, {Kind: yaml.ScalarNode, Value: file}}}
+	case queries.Kind == yaml.SequenceNode:
+		for _, q := range queries.Content {
+			if covers(q.Value, file) {
+				return nil
+			}
+		}
+		queries.Content = append(queries.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: file})
+	}
+
+	var b bytes.Buffer
+	enc := yaml.NewEncoder(&b)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return err
+	}
+	return util.WriteFile(paths.SqlcConfigPath, b.String())
+}
+
+// sqlcEntry finds the sql[] entry whose gen.go.out is paths.SqlcDir.
+func sqlcEntry(doc *yaml.Node) *yaml.Node {
+	if len(doc.Content) == 0 {
+		return nil
+	}
+	list := mapValue(doc.Content[0], "sql")
+	if list == nil || list.Kind != yaml.SequenceNode || len(list.Content) == 0 {
+		return nil
+	}
+	for _, e := range list.Content {
+		if out := mapValue(mapValue(mapValue(e, "gen"), "go"), "out"); out != nil &&
+			filepath.Clean(out.Value) == filepath.Clean(paths.SqlcDir) {
+			return e
+		}
+	}
+	return list.Content[0]
+}
+
+func mapValue(m *yaml.Node, key string) *yaml.Node {
+	if m == nil || m.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			return m.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// covers reports whether a sqlc queries entry (a file or a directory)
+// already includes file.
+func covers(entry, file string) bool {
+	entry, file = filepath.Clean(entry), filepath.Clean(file)
+	return entry == file || strings.HasPrefix(file, entry+string(filepath.Separator))
+}
@@ -0,0 +1,115 @@
+package repo
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/AndreeJait/ntaps/gen/migration"
+	"github.com/AndreeJait/ntaps/internal/util"
+)
+
+// NullableStyles are the --nullable choices for columns without NOT NULL.
+var NullableStyles = []string{"pgtype", "pointer"}
+
+const pgtypeImport = "github.com/jackc/pgx/v5/pgtype"
+
+// pgType is how one Postgres type maps to Go: notNull for NOT NULL
+// columns, null for nullable ones in the pgtype style.
+type pgType struct {
+	notNull, null string
+	imports       []string
+}
+
+var pgTypes = map[string]pgType{}
+
+func init() {
+	add := func(t pgType, names ...string) {
+		for _, n := range names {
+			pgTypes[n] = t
+		}
+	}
+	pg := []string{pgtypeImport}
+	add(pgType{"int16", "pgtype.Int2", pg}, "smallint", "int2", "smallserial", "serial2")
+	add(pgType{"int32", "pgtype.Int4", pg}, "integer", "int", "int4", "serial", "serial4")
+	add(pgType{"int64", "pgtype.Int8", pg}, "bigint", "int8", "bigserial", "serial8")
+	add(pgType{"float32", "pgtype.Float4", pg}, "real", "float4")
+	add(pgType{"float64", "pgtype.Float8", pg}, "double precision", "float8")
+	add(pgType{"pgtype.Numeric", "pgtype.Numeric", pg}, "numeric", "decimal")
+	add(pgType{"bool", "pgtype.Bool", pg}, "boolean", "bool")
+	add(pgType{"string", "pgtype.Text", pg}, "text", "character varying", "varchar", "character", "char", "bpchar", "citext", "name")
+	add(pgType{"pgtype.UUID", "pgtype.UUID", pg}, "uuid")
+	add(pgType{"time.Time", "pgtype.Timestamptz", []string{"time", pgtypeImport}}, "timestamptz", "timestamp with time zone")
+	add(pgType{"time.Time", "pgtype.Timestamp", []string{"time", pgtypeImport}}, "timestamp", "timestamp without time zone")
+	add(pgType{"time.Time", "pgtype.Date", []string{"time", pgtypeImport}}, "date")
+	add(pgType{"pgtype.Time", "pgtype.Time", pg}, "time", "time without time zone")
+	add(pgType{"pgtype.Interval", "pgtype.Interval", pg}, "interval")
+	add(pgType{"[]byte", "[]byte", nil}, "json", "jsonb", "bytea")
+	add(pgType{"netip.Addr", "*netip.Addr", []string{"net/netip"}}, "inet")
+	add(pgType{"netip.Prefix", "*netip.Prefix", []string{"net/netip"}}, "cidr")
+}
+
+// columnType is c's Go type in the given nullable style, recording the
+// imports it needs. Unknown types (enums, domains) map to strings.
+func columnType(c *migration.Column, nullable string, imports map[string]bool) string {
+	t, ok := pgTypes[c.Type]
+	if !ok {
+		fmt.Printf("ℹ️  column %s: unknown type %s, using string\n", c.Name, c.Type)
+		t = pgType{"string", "pgtype.Text", []string{pgtypeImport}}
+	}
+
+	typ := t.notNull
+	switch {
+	case c.Array: // NULL arrays are nil slices
+		typ = "[]" + t.notNull
+	case c.NotNull:
+	case nullable == "pointer" && !strings.HasPrefix(t.notNull, "pgtype.") && !strings.HasPrefix(t.notNull, "[]"):
+		typ = "*" + t.notNull
+	default:
+		typ = t.null
+	}
+	for _, imp := range t.imports {
+		if strings.Contains(typ, imp[strings.LastIndex(imp, "/")+1:]+".") {
+			imports[imp] = true
+		}
+	}
+	return typ
+}
+
+// RunFromTable is Run with the DTOs derived from table as the migrations
+// define it: <Method>Response gets every column, <Method>Param every
+// column without a default (serial, identity, DEFAULT now(), ...).
+func RunFromTable(pkg, method, table, nullable string, withParam, withResp, withTx bool, addToUC string) error {
+	t, err := migration.LoadTable(table)
+	if err != nil {
+		return err
+	}
+	if err := Run(pkg, method, withParam, withResp, withTx, addToUC); err != nil {
+		return err
+	}
+
+	imports := map[string]bool{}
+	var param, resp []util.StructField
+	for _, c := range t.Columns {
+		f := util.StructField{
+			Name: util.ExportedName(c.Name),
+			Type: columnType(c, nullable, imports),
+			Tag:  fmt.Sprintf(`db:"%s"`, c.Name),
+		}
+		resp = append(resp, f)
+		if !c.HasDefault {
+			param = append(param, f)
+		}
+	}
+
+	dtos := map[string][]util.StructField{}
+	var order []string
+	if withParam {
+		order = append(order, method+"Param")
+		dtos[method+"Param"] = param
+	}
+	if withResp {
+		order = append(order, method+"Response")
+		dtos[method+"Response"] = resp
+	}
+	return fillRepoDTOs(pkg, order, dtos, imports)
+}
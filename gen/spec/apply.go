@@ -0,0 +1,92 @@
+package spec
+
+import (
+	"fmt"
+
+	"github.com/AndreeJait/ntaps/gen/handler"
+	"github.com/AndreeJait/ntaps/gen/outbound"
+	"github.com/AndreeJait/ntaps/gen/repo"
+	"github.com/AndreeJait/ntaps/gen/usecase"
+)
+
+// Apply generates everything the spec declares, in dependency order:
+// repositories and outbounds first, then usecases, then repo→usecase wiring,
+// then handlers. Every generator is idempotent, so re-applying is safe.
+func Apply(s *Spec) error {
+	for _, r := range s.Repositories {
+		for _, m := range r.Methods {
+			fmt.Printf("• repository %s.%s\n", r.Pkg, m.Name)
+			if err := repo.Run(r.Pkg, m.Name, m.Param, m.Response, m.Tx, ""); err != nil {
+				return fmt.Errorf("repository %s.%s: %w", r.Pkg, m.Name, err)
+			}
+		}
+	}
+
+	for _, o := range s.Outbounds {
+		if len(o.Methods) == 0 {
+			fmt.Printf("• outbound %s\n", o.Pkg)
+			if err := outbound.Run(o.Pkg, "", false, false); err != nil {
+				return fmt.Errorf("outbound %s: %w", o.Pkg, err)
+			}
+		}
+		for _, m := range o.Methods {
+			fmt.Printf("• outbound %s.%s\n", o.Pkg, m.Name)
+			if err := outbound.Run(o.Pkg, m.Name, m.Param, m.Response); err != nil {
+				return fmt.Errorf("outbound %s.%s: %w", o.Pkg, m.Name, err)
+			}
+		}
+	}
+
+	for _, m := range s.Modules {
+		if len(m.Usecases) == 0 {
+			fmt.Printf("• usecase %s\n", m.Name)
+			if err := usecase.Run(m.Name, "", false, false); err != nil {
+				return fmt.Errorf("usecase %s: %w", m.Name, err)
+			}
+		}
+		for _, u := range m.Usecases {
+			fmt.Printf("• usecase %s.%s\n", m.Name, u.Method)
+			if err := usecase.Run(m.Name, u.Method, u.WithParam, u.WithResponse); err != nil {
+				return fmt.Errorf("usecase %s.%s: %w", m.Name, u.Method, err)
+			}
+		}
+	}
+
+	for _, m := range s.Modules {
+		for _, ref := range m.Repositories {
+			rm, _ := s.repoMethod(ref.Pkg, ref.Method)
+			fmt.Printf("• wire repository %s.%s → usecase %s\n", ref.Pkg, ref.Method, m.Name)
+			if err := repo.AddRepoToUsecase(ref.Pkg, m.Name, rm.Name, rm.Param, rm.Response, rm.Tx); err != nil {
+				return fmt.Errorf("wire %s into %s: %w", ref.Pkg, m.Name, err)
+			}
+		}
+	}
+
+	for _, m := range s.Modules {
+		if len(m.Routes) == 0 {
+			continue
+		}
+		for _, r := range m.Routes {
+			fmt.Printf("• route %s %s → %s.%s\n", r.Verb, r.Endpoint, m.Name, r.UcMethod)
+			if err := handler.Run(
+				m.Handler,
+				m.Name,
+				r.EndpointType,
+				r.Endpoint,
+				*r.WithParam,
+				*r.WithResponse,
+				r.UcMethod,
+				r.Method,
+				r.Tag,
+				r.Verb,
+				r.Status,
+				nil,
+				"",
+			); err != nil {
+				return fmt.Errorf("route %s.%s: %w", m.Handler, r.Method, err)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,221 @@
+package spec
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/AndreeJait/ntaps/gen/handler"
+	"github.com/AndreeJait/ntaps/internal/util"
+)
+
+// Spec describes the whole service surface. It is read from YAML or JSON
+// (JSON is valid YAML, so one decoder handles both).
+type Spec struct {
+	Modules      []Module     `yaml:"modules"`
+	Repositories []Repository `yaml:"repositories"`
+	Outbounds    []Outbound   `yaml:"outbounds"`
+}
+
+// Module is one usecase package plus the handler routes that call it.
+type Module struct {
+	Name         string          `yaml:"name"`
+	Handler      string          `yaml:"handler"` // handler pkg; defaults to Name
+	Usecases     []UsecaseMethod `yaml:"usecases"`
+	Routes       []Route         `yaml:"routes"`
+	Repositories []RepoRef       `yaml:"repositories"` // repo methods injected into this usecase
+}
+
+type UsecaseMethod struct {
+	Method       string `yaml:"method"`
+	WithParam    bool   `yaml:"withParam"`
+	WithResponse bool   `yaml:"withResponse"`
+}
+
+type Route struct {
+	Method       string `yaml:"method"`   // handler method, lowerCamel
+	UcMethod     string `yaml:"ucMethod"` // usecase method, PascalCase
+	Verb         string `yaml:"verb"`
+	EndpointType string `yaml:"endpointType"`
+	Endpoint     string `yaml:"endpoint"`
+	Tag          string `yaml:"tag"`
+	Status       int    `yaml:"status"` // success status; 0 = verb default
+	// WithParam/WithResponse default to the matching entry in Usecases.
+	WithParam    *bool `yaml:"withParam"`
+	WithResponse *bool `yaml:"withResponse"`
+}
+
+type RepoRef struct {
+	Pkg    string `yaml:"pkg"`
+	Method string `yaml:"method"`
+}
+
+type Repository struct {
+	Pkg     string       `yaml:"pkg"`
+	Methods []RepoMethod `yaml:"methods"`
+}
+
+type RepoMethod struct {
+	Name     string `yaml:"name"`
+	Param    bool   `yaml:"param"`
+	Response bool   `yaml:"response"`
+	Tx       bool   `yaml:"tx"`
+}
+
+type Outbound struct {
+	Pkg     string           `yaml:"pkg"`
+	Methods []OutboundMethod `yaml:"methods"`
+}
+
+type OutboundMethod struct {
+	Name     string `yaml:"name"`
+	Param    bool   `yaml:"param"`
+	Response bool   `yaml:"response"`
+}
+
+// Load reads a spec file.
+func Load(path string) (*Spec, error) {
+	raw, err := util.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read spec %s: %w", path, err)
+	}
+	var s Spec
+	dec := yaml.NewDecoder(strings.NewReader(string(raw)))
+	dec.KnownFields(true)
+	if err := dec.Decode(&s); err != nil {
+		return nil, fmt.Errorf("parse spec %s: %w", path, err)
+	}
+	s.normalize()
+	if err := s.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid spec %s: %w", path, err)
+	}
+	return &s, nil
+}
+
+func (s *Spec) normalize() {
+	for i := range s.Modules {
+		m := &s.Modules[i]
+		if m.Handler == "" {
+			m.Handler = m.Name
+		}
+		for j := range m.Routes {
+			r := &m.Routes[j]
+			r.Verb = strings.ToUpper(strings.TrimSpace(r.Verb))
+			if r.Verb == "" {
+				r.Verb = "POST"
+			}
+			if r.EndpointType == "" {
+				r.EndpointType = "public"
+			}
+			if r.Endpoint != "" && r.Endpoint[0] != '/' {
+				r.Endpoint = "/" + r.Endpoint
+			}
+			if r.Tag == "" {
+				r.Tag = util.ToPascalCase(m.Handler)
+			}
+			if uc, ok := m.usecase(r.UcMethod); ok {
+				if r.WithParam == nil {
+					r.WithParam = &uc.WithParam
+				}
+				if r.WithResponse == nil {
+					r.WithResponse = &uc.WithResponse
+				}
+			}
+			if r.WithParam == nil {
+				r.WithParam = new(bool)
+			}
+			if r.WithResponse == nil {
+				r.WithResponse = new(bool)
+			}
+		}
+	}
+}
+
+// Validate checks the same rules the individual create-* commands enforce.
+func (s *Spec) Validate() error {
+	for _, m := range s.Modules {
+		if m.Name == "" {
+			return fmt.Errorf("module without name")
+		}
+		for _, u := range m.Usecases {
+			if !isPascal(u.Method) {
+				return fmt.Errorf("module %s: usecase method %q must be PascalCase", m.Name, u.Method)
+			}
+		}
+		for _, r := range m.Routes {
+			if r.Method == "" || r.Endpoint == "" || !isPascal(r.UcMethod) {
+				return fmt.Errorf("module %s: route needs method, endpoint and a PascalCase ucMethod", m.Name)
+			}
+			if !handler.IsVerb(r.Verb) {
+				return fmt.Errorf("module %s: route %s: verb must be one of %s", m.Name, r.Method, strings.Join(handler.Verbs, "|"))
+			}
+			if r.Status != 0 {
+				if err := handler.CheckStatus(r.Status, *r.WithResponse); err != nil {
+					return fmt.Errorf("module %s: route %s: %w", m.Name, r.Method, err)
+				}
+			}
+			switch strings.ToLower(r.EndpointType) {
+			case "public", "internal", "private":
+			default:
+				return fmt.Errorf("module %s: route %s: endpointType must be public|internal|private", m.Name, r.Method)
+			}
+		}
+		for _, ref := range m.Repositories {
+			if ref.Pkg == "" || !isPascal(ref.Method) {
+				return fmt.Errorf("module %s: repository ref needs pkg and a PascalCase method", m.Name)
+			}
+			if _, ok := s.repoMethod(ref.Pkg, ref.Method); !ok {
+				return fmt.Errorf("module %s: repository %s.%s is not declared under repositories", m.Name, ref.Pkg, ref.Method)
+			}
+		}
+	}
+	for _, r := range s.Repositories {
+		if r.Pkg == "" {
+			return fmt.Errorf("repository without pkg")
+		}
+		for _, m := range r.Methods {
+			if !isPascal(m.Name) {
+				return fmt.Errorf("repository %s: method %q must be PascalCase", r.Pkg, m.Name)
+			}
+		}
+	}
+	for _, o := range s.Outbounds {
+		if o.Pkg == "" {
+			return fmt.Errorf("outbound without pkg")
+		}
+		for _, m := range o.Methods {
+			if !isPascal(m.Name) {
+				return fmt.Errorf("outbound %s: method %q must be PascalCase", o.Pkg, m.Name)
+			}
+		}
+	}
+	return nil
+}
+
+func (m Module) usecase(method string) (UsecaseMethod, bool) {
+	for _, u := range m.Usecases {
+		if u.Method == method {
+			return u, true
+		}
+	}
+	return UsecaseMethod{}, false
+}
+
+func (s *Spec) repoMethod(pkg, method string) (RepoMethod, bool) {
+	for _, r := range s.Repositories {
+		if r.Pkg != pkg {
+			continue
+		}
+		for _, m := range r.Methods {
+			if m.Name == method {
+				return m, true
+			}
+		}
+	}
+	return RepoMethod{}, false
+}
+
+func isPascal(s string) bool {
+	return s != "" && strings.ToUpper(s[:1]) == s[:1]
+}
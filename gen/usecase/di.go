@@ -7,44 +7,44 @@ import (
 	"strings"
 
 	"github.com/AndreeJait/ntaps/internal/paths"
+	"github.com/AndreeJait/ntaps/internal/tmpl"
 	"github.com/AndreeJait/ntaps/internal/util"
 )
 
 func updateUsecaseDI(pkg string) error {
 	path := paths.UsecaseDIPath
 
-	if _, err := os.Stat(path); os.IsNotExist(err) {
-		content := fmt.Sprintf(`package usecase
-
-import (
-	"%s/internal/usecase/%s"
-)
-
-type UseCase struct {
-	%sUc %s.UseCase
-}
-`, util.ModulePathGuess(), pkg, util.ToPascalCase(pkg), pkg)
+	if _, err := util.Stat(path); os.IsNotExist(err) {
+		content, err := tmpl.Render("usecase_di.tmpl", tmpl.UsecaseDI{
+			Pkg:          pkg,
+			PkgPascal:    util.ToPascalCase(pkg),
+			Import:       util.ImportPath(paths.RootUsecaseDir, pkg),
+			StructMarker: paths.UsecaseStructMarker,
+		})
+		if err != nil {
+			return err
+		}
 		return util.WriteGoFile(path, content)
 	}
 
-	raw, err := os.ReadFile(path)
+	raw, err := util.ReadFile(path)
 	if err != nil {
 		return err
 	}
 	body := string(raw)
 
-	importLine := fmt.Sprintf(`"%s/internal/usecase/%s"`, util.ModulePathGuess(), pkg)
+	importLine := fmt.Sprintf(`"%s"`, util.ImportPath(paths.RootUsecaseDir, pkg))
 	if !strings.Contains(body, importLine) {
 		body = util.InsertImport(body, importLine)
 	}
 
 	field := fmt.Sprintf("\t%sUc %s.UseCase", util.ToPascalCase(pkg), pkg)
-	if !strings.Contains(body, field) {
-		body = strings.Replace(body,
-			"type UseCase struct {",
-			"type UseCase struct {\n"+field+"\n",
-			1,
-		)
+	if !util.HasField(body, util.ToPascalCase(pkg)+"Uc", pkg+".UseCase") {
+		loc := util.MarkerRegexp(paths.UsecaseStructMarker).FindStringIndex(body)
+		if loc == nil {
+			return fmt.Errorf("%q not found in %s", paths.UsecaseStructMarker, path)
+		}
+		body = body[:loc[1]] + "\n" + field + "\n" + body[loc[1]:]
 	}
 	return util.WriteGoFile(path, body)
 }
@@ -52,39 +52,35 @@ type UseCase struct {
 func updateInfraInitUsecase(pkg string) error {
 	path := paths.InfraInitUsecasePath
 
-	raw, err := os.ReadFile(path)
+	raw, err := util.ReadFile(path)
 	if err != nil {
 		return fmt.Errorf("cannot read %s: %w", path, err)
 	}
 	body := string(raw)
 
 	// 1. ensure import of this usecase pkg
-	importLine := fmt.Sprintf(`"%s/internal/usecase/%s"`, util.ModulePathGuess(), pkg)
+	importLine := fmt.Sprintf(`"%s"`, util.ImportPath(paths.RootUsecaseDir, pkg))
 	if !strings.Contains(body, importLine) {
 		body = util.InsertImport(body, importLine)
 	}
 
 	// 2. ensure initUseCase() exists
-	if !strings.Contains(body, "func (s wire) initUseCase(") &&
-		!strings.Contains(body, "func (s wire) initUseCase()") {
-		body += `
-
-func (s wire) initUseCase() {
-	// generated by ntaps
-}
-`
+	fnHeaderRe := util.MarkerRegexp(paths.UsecaseInitMarker)
+	if !fnHeaderRe.MatchString(body) {
+		body += "\n\n" + paths.UsecaseInitMarker + ") {\n\t// generated by ntaps\n}\n"
 	}
 
 	// 3. determine the default assignment line
 	assignLine := fmt.Sprintf(
-		`s.uc.%sUc = %s.NewUseCase(s.cfg, s.log, s.repo.TxManager)`,
+		`%[1]s.uc.%[2]sUc = %[3]s.NewUseCase(%[1]s.cfg, %[1]s.log, %[1]s.repo.TxManager)`,
+		paths.WireReceiver,
 		util.ToPascalCase(pkg),
 		pkg,
 	)
 
 	// 4. check if this UC is already assigned anywhere
 	alreadyAssignedRe := regexp.MustCompile(
-		fmt.Sprintf(`s\.uc\.%sUc\s*=\s*`, util.ToPascalCase(pkg)),
+		fmt.Sprintf(`%s\.uc\.%sUc\s*=\s*`, regexp.QuoteMeta(paths.WireReceiver), util.ToPascalCase(pkg)),
 	)
 	if alreadyAssignedRe.MatchString(body) {
 		// already wired, no duplicate insert
@@ -92,12 +88,14 @@ func (s wire) initUseCase() {
 	}
 
 	// 5. inject the default assignment in initUseCase()
-	fnHeaderRe := regexp.MustCompile(`func\s+\(s\s+wire\)\s+initUseCase\([^\)]*\)\s*\{`)
 	loc := fnHeaderRe.FindStringIndex(body)
 	if loc == nil {
-		return fmt.Errorf("initUseCase() not found in %s", path)
+		return fmt.Errorf("%q not found in %s", paths.UsecaseInitMarker, path)
+	}
+	insertAt := util.FuncBodyStart(body, loc[1])
+	if insertAt == -1 {
+		return fmt.Errorf("body of %q not found in %s", paths.UsecaseInitMarker, path)
 	}
-	insertAt := loc[1]
 
 	body = body[:insertAt] + "\n\t" + assignLine + "\n" + body[insertAt:]
 
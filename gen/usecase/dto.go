@@ -7,6 +7,8 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/AndreeJait/ntaps/internal/paths"
+	"github.com/AndreeJait/ntaps/internal/tmpl"
 	"github.com/AndreeJait/ntaps/internal/util"
 )
 
@@ -14,12 +16,20 @@ func createDTO(dir, pkg, method string, withParam, withResp bool) error {
 	path := filepath.Join(dir, "dto.go")
 
 	var b bytes.Buffer
-	b.WriteString("package " + pkg + "\n\n")
+	b.WriteString("package " + pkg + "\n")
 	if withParam {
-		b.WriteString(fmt.Sprintf("type %sRequest struct {\n\t// TODO: define fields\n}\n\n", method))
+		s, err := tmpl.Render("dto_struct.tmpl", tmpl.DTO{Pkg: pkg, Name: method + "Request"})
+		if err != nil {
+			return err
+		}
+		b.WriteString(s)
 	}
 	if withResp {
-		b.WriteString(fmt.Sprintf("type %sResponse struct {\n\t// TODO: define fields\n}\n\n", method))
+		s, err := tmpl.Render("dto_struct.tmpl", tmpl.DTO{Pkg: pkg, Name: method + "Response"})
+		if err != nil {
+			return err
+		}
+		b.WriteString(s)
 	}
 	return util.WriteGoFile(path, b.String())
 }
@@ -27,21 +37,59 @@ func createDTO(dir, pkg, method string, withParam, withResp bool) error {
 func ensureDTO(dir, pkg, method string, withParam, withResp bool) error {
 	path := filepath.Join(dir, "dto.go")
 
-	if _, err := os.Stat(path); os.IsNotExist(err) {
+	if _, err := util.Stat(path); os.IsNotExist(err) {
 		return createDTO(dir, pkg, method, withParam, withResp)
 	}
 
-	raw, err := os.ReadFile(path)
+	raw, err := util.ReadFile(path)
 	if err != nil {
 		return err
 	}
 	out := string(raw)
 
-	if withParam && !strings.Contains(out, "type "+method+"Request struct") {
-		out += fmt.Sprintf("\n// %sRequest generated by ntaps\ntype %sRequest struct {\n\t// TODO: define fields\n}\n", method, method)
-	}
-	if withResp && !strings.Contains(out, "type "+method+"Response struct") {
-		out += fmt.Sprintf("\n// %sResponse generated by ntaps\ntype %sResponse struct {\n\t// TODO: define fields\n}\n", method, method)
+	for _, name := range []string{
+		util.Conditional(withParam, method+"Request"),
+		util.Conditional(withResp, method+"Response"),
+	} {
+		if name == "" || strings.Contains(out, "type "+name+" struct") {
+			continue
+		}
+		s, err := tmpl.Render("dto_struct.tmpl", tmpl.DTO{Pkg: pkg, Name: name, Comment: name + " generated by ntaps"})
+		if err != nil {
+			return err
+		}
+		out += s
 	}
 	return util.WriteGoFile(path, out)
 }
+
+// EnsureDTOType adds fields to `type <name> struct` in the dto.go at path,
+// appending the type first if it is missing. Existing fields are kept.
+func EnsureDTOType(path, pkg, name string, fields []util.StructField) error {
+	raw, err := util.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	src := string(raw)
+	if !strings.Contains(src, "type "+name+" struct") {
+		s, err := tmpl.Render("dto_struct.tmpl", tmpl.DTO{Pkg: pkg, Name: name, Comment: name + " generated by ntaps"})
+		if err != nil {
+			return err
+		}
+		src += s
+	}
+	src, err = util.EnsureStructFields(src, name, fields)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	for _, imp := range util.FieldImports(fields) {
+		src = util.InsertImport(src, `"`+imp+`"`)
+	}
+	return util.WriteGoFile(path, src)
+}
+
+// AddFields adds fields to `type <name> struct` in the usecase package's
+// dto.go (e.g. --field values for <Method>Request).
+func AddFields(pkg, name string, fields []util.StructField) error {
+	return EnsureDTOType(filepath.Join(paths.RootUsecaseDir, pkg, "dto.go"), pkg, name, fields)
+}
@@ -6,19 +6,24 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/AndreeJait/ntaps/internal/paths"
+	"github.com/AndreeJait/ntaps/internal/tmpl"
 	"github.com/AndreeJait/ntaps/internal/util"
 )
 
 func createImpl(dir, pkg, method string, withParam, withResp bool) error {
 	path := filepath.Join(dir, "usecase.go")
-	body := renderImpl(pkg, method, withParam, withResp)
+	body, err := renderImpl(pkg, method, withParam, withResp)
+	if err != nil {
+		return err
+	}
 	return util.WriteGoFile(path, body)
 }
 
 func ensureImpl(dir, pkg, method string, withParam, withResp bool) error {
 	path := filepath.Join(dir, "usecase.go")
 
-	b, err := os.ReadFile(path)
+	b, err := util.ReadFile(path)
 	if os.IsNotExist(err) {
 		return createImpl(dir, pkg, method, withParam, withResp)
 	}
@@ -27,12 +32,12 @@ func ensureImpl(dir, pkg, method string, withParam, withResp bool) error {
 	}
 
 	src := string(b)
-	mod := util.ModulePathGuess()
+	data := templateData(pkg, method, withParam, withResp)
 
 	// required imports
 	reqImports := []string{
-		fmt.Sprintf(`"%s/internal/infrastructure/config"`, mod),
-		fmt.Sprintf(`"%s/internal/infrastructure/db"`, mod),
+		fmt.Sprintf(`"%s"`, data.ConfigImport),
+		fmt.Sprintf(`"%s"`, data.DBImport),
 		`"github.com/AndreeJait/go-utility/loggerw"`,
 		`"github.com/AndreeJait/go-utility/tracer"`,
 		`"context"`,
@@ -44,17 +49,11 @@ func ensureImpl(dir, pkg, method string, withParam, withResp bool) error {
 
 	// ensure useCase struct & NewUseCase signature
 	if !strings.Contains(src, "type useCase struct") {
-		src += `
-type useCase struct {
-	cfg       *config.Config
-	log       loggerw.Logger
-	txManager *db.TxManager
-}
-
-func NewUseCase(cfg *config.Config, log loggerw.Logger, txManager *db.TxManager) UseCase {
-	return &useCase{cfg: cfg, log: log, txManager: txManager}
-}
-`
+		structSrc, err := tmpl.Render("usecase_struct.tmpl", data)
+		if err != nil {
+			return err
+		}
+		src += "\n" + structSrc
 	} else {
 		// normalize txManager *db.TxManager
 		src = strings.ReplaceAll(src, "txManager  db.TxManager", "txManager *db.TxManager")
@@ -69,80 +68,44 @@ func NewUseCase(cfg *config.Config, log loggerw.Logger, txManager *db.TxManager)
 	// ensure method exists
 	methodSig := fmt.Sprintf("func (u *useCase) %s(", method)
 	if !strings.Contains(src, methodSig) {
-		sigIn := "ctx context.Context"
-		if withParam {
-			sigIn += ", req " + method + "Request"
-		}
-		ret := "error"
-		var retBody string
-		if withResp {
-			ret = "(" + method + "Response, error)"
-			retBody = "var resp " + method + "Response\n\t// TODO: implement\n\treturn resp, nil"
-		} else {
-			retBody = "// TODO: implement\n\treturn nil"
+		methodSrc, err := tmpl.Render("usecase_method.tmpl", data)
+		if err != nil {
+			return err
 		}
-
-		src += fmt.Sprintf(`
-
-func (u *useCase) %s(%s) %s {
-	span, ctx := tracer.StartSpan(ctx, tracer.GetFuncName(u.%s))
-	defer span.End()
-
-	%s
-}
-`, method, sigIn, ret, method, retBody)
+		src += methodSrc
 	}
 
 	return util.WriteGoFile(path, src)
 }
 
-func renderImpl(pkg, method string, withParam, withResp bool) string {
-	mp := util.ModulePathGuess()
+func renderImpl(pkg, method string, withParam, withResp bool) (string, error) {
+	return tmpl.Render("usecase_impl.tmpl", templateData(pkg, method, withParam, withResp))
+}
+
+// templateData builds what every usecase_*.tmpl receives.
+func templateData(pkg, method string, withParam, withResp bool) tmpl.Usecase {
+	return tmpl.Usecase{
+		Pkg:          pkg,
+		Method:       method,
+		WithParam:    withParam,
+		WithResponse: withResp,
+		Signature:    signature(method, withParam, withResp),
+		ConfigImport: util.ImportPath(paths.ConfigDir),
+		DBImport:     util.ImportPath(paths.InfraDBDir),
+	}
+}
 
-	sigIn := "ctx context.Context"
+// signature renders "<Method>(ctx context.Context[, req <Method>Request]) <ret>".
+func signature(method string, withParam, withResp bool) string {
+	req := "ctx context.Context"
 	if withParam {
-		sigIn += ", req " + method + "Request"
+		req += ", req " + method + "Request"
 	}
 	ret := "error"
-	retBody := "// TODO: implement\n\treturn nil"
 	if withResp {
 		ret = "(" + method + "Response, error)"
-		retBody = "var resp " + method + "Response\n\t// TODO: implement\n\treturn resp, nil"
 	}
-
-	return fmt.Sprintf(`package %s
-
-import (
-	"context"
-	"time"
-
-	"github.com/AndreeJait/go-utility/loggerw"
-	"github.com/AndreeJait/go-utility/tracer"
-
-	"%s/internal/infrastructure/config"
-	"%s/internal/infrastructure/db"
-)
-
-// useCase implements UseCase.
-type useCase struct {
-	cfg       *config.Config
-	log       loggerw.Logger
-	txManager *db.TxManager
-}
-
-func NewUseCase(cfg *config.Config, log loggerw.Logger, txManager *db.TxManager) UseCase {
-	return &useCase{cfg: cfg, log: log, txManager: txManager}
-}
-
-func (u *useCase) %s(%s) %s {
-	span, ctx := tracer.StartSpan(ctx, tracer.GetFuncName(u.%s))
-	defer span.End()
-
-	_ = time.Second // keep import 'time' warm; remove when used
-
-	%s
-}
-`, pkg, mp, mp, method, sigIn, ret, method, retBody)
+	return fmt.Sprintf("%s(%s) %s", method, req, ret)
 }
 
 // ---- wiring ----
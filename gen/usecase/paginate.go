@@ -0,0 +1,55 @@
+package usecase
+
+import (
+	"fmt"
+
+	"github.com/AndreeJait/ntaps/internal/util"
+)
+
+// PageStyles are the --paginated modes: offset (page/limit + total) and
+// cursor (keyset: cursor/limit + next_cursor).
+var PageStyles = []string{"offset", "cursor"}
+
+// DefaultLimit and MaxLimit bound the limit query param.
+const (
+	DefaultLimit = 20
+	MaxLimit     = 100
+)
+
+// PageItem is the element type of a paginated <method>Response.
+func PageItem(method string) string { return method + "Item" }
+
+// Paginate adds the page params to <method>Request and the page envelope
+// (items plus total or next_cursor) to <method>Response. The items are
+// <method>Item, created with a TODO on first use.
+func Paginate(pkg, method, style string) error {
+	limit := util.StructField{
+		Name: "Limit",
+		Type: "int",
+		Tag:  fmt.Sprintf(`query:"limit" default:"%d" validate:"min=1,max=%d"`, DefaultLimit, MaxLimit),
+	}
+	req := []util.StructField{{Name: "Page", Type: "int", Tag: `query:"page" default:"1" validate:"min=1"`}, limit}
+	resp := []util.StructField{
+		{Name: "Items", Type: "[]" + PageItem(method), Tag: `json:"items"`},
+		{Name: "Total", Type: "int64", Tag: `json:"total"`},
+	}
+	switch style {
+	case "offset":
+	case "cursor":
+		req = []util.StructField{{Name: "Cursor", Type: "string", Tag: `query:"cursor"`}, limit}
+		resp[1] = util.StructField{Name: "NextCursor", Type: "string", Tag: `json:"next_cursor"`}
+	default:
+		return fmt.Errorf("pagination %q: use %v", style, PageStyles)
+	}
+
+	if err := AddFields(pkg, PageItem(method), nil); err != nil {
+		return err
+	}
+	if err := AddFields(pkg, method+"Request", req); err != nil {
+		return err
+	}
+	if err := AddFields(pkg, method+"Response", resp); err != nil {
+		return err
+	}
+	return SyncValidators(pkg)
+}
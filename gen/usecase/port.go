@@ -1,41 +1,37 @@
 package usecase
 
 import (
-	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
 
+	"github.com/AndreeJait/ntaps/internal/tmpl"
 	"github.com/AndreeJait/ntaps/internal/util"
 )
 
 func createPort(dir, pkg, method string, withParam, withResp bool) error {
 	path := filepath.Join(dir, "port.go")
-	return util.WriteGoFile(path, renderPort(pkg, method, withParam, withResp))
+	body, err := tmpl.Render("usecase_port.tmpl", templateData(pkg, method, withParam, withResp))
+	if err != nil {
+		return err
+	}
+	return util.WriteGoFile(path, body)
 }
 
 func ensurePort(dir, pkg, method string, withParam, withResp bool) error {
 	path := filepath.Join(dir, "port.go")
 
-	if _, err := os.Stat(path); os.IsNotExist(err) {
+	if _, err := util.Stat(path); os.IsNotExist(err) {
 		return createPort(dir, pkg, method, withParam, withResp)
 	}
 
-	b, err := os.ReadFile(path)
+	b, err := util.ReadFile(path)
 	if err != nil {
 		return err
 	}
 	src := string(b)
 
-	req := "ctx context.Context"
-	if withParam {
-		req += ", req " + method + "Request"
-	}
-	ret := "error"
-	if withResp {
-		ret = "(" + method + "Response, error)"
-	}
-	newLine := fmt.Sprintf("\t%s(%s) %s\n", method, req, ret)
+	newLine := "\t" + signature(method, withParam, withResp) + "\n"
 
 	// ensure package line
 	if !strings.Contains(src, "package "+pkg) {
@@ -73,22 +69,3 @@ func ensurePort(dir, pkg, method string, withParam, withResp bool) error {
 	src = src[:insertPos] + "\n" + newLine + src[insertPos:]
 	return util.WriteGoFile(path, src)
 }
-
-func renderPort(pkg, method string, withParam, withResp bool) string {
-	req := "ctx context.Context"
-	if withParam {
-		req += ", req " + method + "Request"
-	}
-	ret := "error"
-	if withResp {
-		ret = "(" + method + "Response, error)"
-	}
-	return fmt.Sprintf(`package %s
-
-import "context"
-
-type UseCase interface {
-	%s(%s) %s
-}
-`, pkg, method, req, ret)
-}
@@ -0,0 +1,63 @@
+package usecase
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+
+	"github.com/AndreeJait/ntaps/internal/paths"
+	"github.com/AndreeJait/ntaps/internal/util"
+)
+
+// RemoveMethod reverses create-usecase for one method: the UseCase port
+// entry, the useCase implementation and its Request/Response DTOs. It
+// refuses while handlers (or anything outside the pkg) still call it.
+func RemoveMethod(pkg, method string, force bool) error {
+	dir := filepath.Join(paths.RootUsecaseDir, pkg)
+	portPath := filepath.Join(dir, "port.go")
+
+	raw, err := util.ReadFile(portPath)
+	if err != nil {
+		return fmt.Errorf("usecase %q not found: %w", pkg, err)
+	}
+	port, ok := util.RemoveInterfaceMethod(string(raw), "UseCase", method)
+	if !ok {
+		return fmt.Errorf("method %s not found in %s", method, portPath)
+	}
+
+	refs := util.FindReferences(regexp.MustCompile(fmt.Sprintf(
+		`\.%sUc\.%s\(|\b%s\.%s(Request|Response)\b`,
+		util.ToPascalCase(pkg), method, regexp.QuoteMeta(pkg), method,
+	)), dir)
+	if err := util.GuardReferences(pkg+"."+method, refs, force); err != nil {
+		return err
+	}
+
+	if err := util.WriteGoFile(portPath, port); err != nil {
+		return err
+	}
+
+	implPath := filepath.Join(dir, "usecase.go")
+	if raw, err := util.ReadFile(implPath); err == nil {
+		if src, ok := util.RemoveFunc(string(raw), "useCase", method); ok {
+			if err := util.WriteGoFile(implPath, src); err != nil {
+				return err
+			}
+		}
+	}
+
+	dtoPath := filepath.Join(dir, "dto.go")
+	if raw, err := util.ReadFile(dtoPath); err == nil {
+		src := string(raw)
+		src, reqOK := util.RemoveType(src, method+"Request")
+		src, respOK := util.RemoveType(src, method+"Response")
+		if reqOK || respOK {
+			if err := util.WriteGoFile(dtoPath, src); err != nil {
+				return err
+			}
+		}
+	}
+
+	// drop the removed DTOs' Validate() methods
+	return SyncValidators(pkg)
+}
@@ -7,14 +7,15 @@ import (
 	"path/filepath"
 
 	"github.com/AndreeJait/ntaps/internal/paths"
+	"github.com/AndreeJait/ntaps/internal/util"
 )
 
 // Run creates or extends a usecase package and wires DI.
 func Run(pkg, method string, withParam, withResp bool) error {
 	pkgDir := filepath.Join(paths.RootUsecaseDir, pkg)
 
-	if _, err := os.Stat(pkgDir); errors.Is(err, os.ErrNotExist) {
-		if err := os.MkdirAll(pkgDir, 0o755); err != nil {
+	if _, err := util.Stat(pkgDir); errors.Is(err, os.ErrNotExist) {
+		if err := util.MkdirAll(pkgDir); err != nil {
 			return fmt.Errorf("mkdir %s: %w", pkgDir, err)
 		}
 		if method != "" {
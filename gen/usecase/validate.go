@@ -0,0 +1,282 @@
+package usecase
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/AndreeJait/ntaps/internal/paths"
+	"github.com/AndreeJait/ntaps/internal/tmpl"
+	"github.com/AndreeJait/ntaps/internal/util"
+)
+
+// ValidateRules are the validate tag rules the generated Validate()
+// methods understand; omitempty skips the others for zero values.
+var ValidateRules = []string{"required", "min", "max", "email", "oneof", "omitempty"}
+
+// validatorHeader marks validate.go as ntaps output, rewritten from the
+// tags on every sync.
+const validatorHeader = "// Code generated by ntaps from the validate tags in dto.go. DO NOT EDIT."
+
+// CheckRules reports the first rule in rules ("required,max=50") the
+// generated validator does not support, or a malformed one.
+func CheckRules(rules string) error {
+	for _, r := range strings.Split(rules, ",") {
+		name, arg, hasArg := strings.Cut(strings.TrimSpace(r), "=")
+		switch name {
+		case "required", "email", "omitempty":
+			if hasArg {
+				return fmt.Errorf("validate rule %s takes no value", name)
+			}
+		case "min", "max":
+			if _, err := strconv.ParseFloat(arg, 64); err != nil {
+				return fmt.Errorf("validate rule %s needs a number (e.g. %s=3)", name, name)
+			}
+		case "oneof":
+			if strings.TrimSpace(arg) == "" {
+				return fmt.Errorf("validate rule oneof needs values (e.g. oneof=draft sent)")
+			}
+		default:
+			return fmt.Errorf("unsupported validate rule %q (use %s)", r, strings.Join(ValidateRules, ", "))
+		}
+	}
+	return nil
+}
+
+// SetValidation sets the validate tag of the field of typeName matched by
+// name (Go name or its json/query/param/form/header/cookie name).
+func SetValidation(pkg, typeName, name, rules string) error {
+	path := filepath.Join(paths.RootUsecaseDir, pkg, "dto.go")
+	raw, err := util.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	src, err := util.SetFieldTag(string(raw), typeName, name, "validate", rules)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return util.WriteGoFile(path, src)
+}
+
+// SyncValidators rewrites validate.go in the usecase package: one
+// Validate() error per DTO with validate tags, using only the standard
+// library. validate.go is removed once no DTO has tags left.
+func SyncValidators(pkg string) error {
+	dir := filepath.Join(paths.RootUsecaseDir, pkg)
+	out := filepath.Join(dir, "validate.go")
+
+	raw, err := util.ReadFile(filepath.Join(dir, "dto.go"))
+	if err != nil {
+		return nil // no DTOs yet
+	}
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "dto.go", raw, 0)
+	if err != nil {
+		return fmt.Errorf("parse %s/dto.go: %w", dir, err)
+	}
+
+	data := tmpl.Validator{Pkg: pkg, Header: validatorHeader}
+	imports := map[string]bool{}
+	for _, d := range f.Decls {
+		gd, ok := d.(*ast.GenDecl)
+		if !ok {
+			continue
+		}
+		for _, spec := range gd.Specs {
+			ts, ok := spec.(*ast.TypeSpec)
+			if !ok {
+				continue
+			}
+			st, ok := ts.Type.(*ast.StructType)
+			if !ok {
+				continue
+			}
+			if t, ok := validatorType(ts.Name.Name, st, imports); ok {
+				data.Types = append(data.Types, t)
+			}
+		}
+	}
+
+	existing, err := util.ReadFile(out)
+	generated := err == nil && strings.HasPrefix(string(existing), validatorHeader)
+	if err == nil && !generated {
+		return fmt.Errorf("%s exists and was not generated by ntaps; not overwriting", out)
+	}
+	if len(data.Types) == 0 {
+		if generated {
+			return util.RemoveFile(out)
+		}
+		return nil
+	}
+	data.Email = imports["net/mail"]
+	for imp := range imports {
+		data.Imports = append(data.Imports, imp)
+	}
+	sort.Strings(data.Imports)
+	src, err := tmpl.Render("validate_file.tmpl", data)
+	if err != nil {
+		return err
+	}
+	return util.WriteGoFile(out, src)
+}
+
+// HasValidator reports whether the usecase DTO typeName has a generated
+// Validate().
+func HasValidator(pkg, typeName string) bool {
+	raw, err := util.ReadFile(filepath.Join(paths.RootUsecaseDir, pkg, "validate.go"))
+	return err == nil && strings.Contains(string(raw), fmt.Sprintf("func (r %s) Validate() error", typeName))
+}
+
+// ValidationSummary lists "field rules" for the swagger 400 description,
+// e.g. "email required,email; name max=50".
+func ValidationSummary(pkg, typeName string) string {
+	raw, err := util.ReadFile(filepath.Join(paths.RootUsecaseDir, pkg, "dto.go"))
+	if err != nil {
+		return ""
+	}
+	var parts []string
+	for _, fl := range util.StructTags(string(raw), typeName) {
+		tag := reflect.StructTag(fl.Tag)
+		if rules := tag.Get("validate"); rules != "" {
+			parts = append(parts, fieldLabel(fl.Name, tag)+" "+rules)
+		}
+	}
+	return strings.Join(parts, "; ")
+}
+
+func validatorType(name string, st *ast.StructType, imports map[string]bool) (tmpl.ValidatorType, bool) {
+	t := tmpl.ValidatorType{Name: name}
+	for _, fl := range st.Fields.List {
+		if fl.Tag == nil || len(fl.Names) == 0 {
+			continue
+		}
+		unquoted, _ := strconv.Unquote(fl.Tag.Value)
+		tag := reflect.StructTag(unquoted)
+		rules := tag.Get("validate")
+		if rules == "" {
+			continue
+		}
+		typ := types.ExprString(fl.Type)
+		for _, n := range fl.Names {
+			t.Checks = append(t.Checks, fieldChecks("r."+n.Name, typ, fieldLabel(n.Name, tag), rules, imports)...)
+		}
+	}
+	return t, len(t.Checks) > 0
+}
+
+// fieldLabel is the name a client knows the field by.
+func fieldLabel(goName string, tag reflect.StructTag) string {
+	for _, key := range []string{"json", "query", "param", "form", "header", "cookie"} {
+		if v, _, _ := strings.Cut(tag.Get(key), ","); v != "" && v != "-" {
+			return v
+		}
+	}
+	return goName
+}
+
+// fieldChecks turns one field's rules into checks. Pointers must be
+// non-nil for required and are checked through otherwise; rules that do
+// not fit the type are skipped with a notice.
+func fieldChecks(v, typ, label, rules string, imports map[string]bool) []tmpl.ValidatorCheck {
+	var out []tmpl.ValidatorCheck
+	guard, ptr := "", ""
+	if strings.HasPrefix(typ, "*") {
+		ptr = v
+		guard = ptr + " != nil && "
+		typ, v = typ[1:], "*"+v
+	}
+	kind := kindOf(typ)
+	if strings.Contains(","+rules+",", ",omitempty,") && kind != "" {
+		guard += "!(" + zero(v, kind) + ") && "
+	}
+
+	for _, r := range strings.Split(rules, ",") {
+		name, arg, _ := strings.Cut(strings.TrimSpace(r), "=")
+		var c tmpl.ValidatorCheck
+		switch {
+		case name == "omitempty":
+			continue
+		case name == "required" && ptr != "":
+			out = append(out, tmpl.ValidatorCheck{Cond: ptr + " == nil", Msg: label + " is required"})
+			continue
+		case name == "required" && kind != "":
+			c = tmpl.ValidatorCheck{Cond: zero(v, kind), Msg: label + " is required"}
+		case (name == "min" || name == "max") && (kind == "string" || kind == "number" || kind == "len"):
+			op, word := "<", "at least"
+			if name == "max" {
+				op, word = ">", "at most"
+			}
+			size, unit := v, ""
+			switch kind {
+			case "string":
+				size, unit = "utf8.RuneCountInString("+v+")", " characters"
+				imports["unicode/utf8"] = true
+			case "len":
+				size, unit = "len("+v+")", " items"
+			}
+			c = tmpl.ValidatorCheck{Cond: fmt.Sprintf("%s %s %s", size, op, arg), Msg: fmt.Sprintf("%s must be %s %s%s", label, word, arg, unit)}
+		case name == "email" && kind == "string":
+			c = tmpl.ValidatorCheck{Cond: "!isEmail(" + v + ")", Msg: label + " must be a valid email address"}
+			imports["net/mail"] = true
+		case name == "oneof" && (kind == "string" || kind == "number"):
+			var conds []string
+			for _, o := range strings.Fields(arg) {
+				if kind == "string" {
+					o = strconv.Quote(o)
+				}
+				conds = append(conds, v+" != "+o)
+			}
+			c = tmpl.ValidatorCheck{Cond: strings.Join(conds, " && "), Msg: label + " must be one of " + arg}
+		default:
+			fmt.Printf("ℹ️  validate rule %q on %s (%s) is not supported by the generated validator, skipped\n", r, label, typ)
+			continue
+		}
+		if guard != "" {
+			c.Cond = guard + "(" + c.Cond + ")"
+		}
+		out = append(out, c)
+	}
+	return out
+}
+
+// kindOf groups Go types by how they are checked; "" is unsupported.
+func kindOf(typ string) string {
+	switch {
+	case typ == "string":
+		return "string"
+	case typ == "bool":
+		return "bool"
+	case typ == "time.Time":
+		return "time"
+	case strings.HasPrefix(typ, "[]"), strings.HasPrefix(typ, "map["):
+		return "len"
+	}
+	switch typ {
+	case "int", "int8", "int16", "int32", "int64",
+		"uint", "uint8", "uint16", "uint32", "uint64",
+		"float32", "float64":
+		return "number"
+	}
+	return ""
+}
+
+func zero(v, kind string) string {
+	switch kind {
+	case "string":
+		return v + ` == ""`
+	case "bool":
+		return "!" + v
+	case "time":
+		return v + ".IsZero()"
+	case "len":
+		return "len(" + v + ") == 0"
+	}
+	return v + " == 0"
+}
@@ -0,0 +1,154 @@
+package usecase
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/AndreeJait/ntaps/internal/tmpl"
+)
+
+func TestCheckRules(t *testing.T) {
+	tests := []struct {
+		rules   string
+		wantErr string
+	}{
+		{"required", ""},
+		{"required,email", ""},
+		{"omitempty, min=3 ,max=50", ""},
+		{"min=0.5", ""},
+		{"oneof=draft sent", ""},
+		{"required=true", "takes no value"},
+		{"min", "needs a number"},
+		{"max=ten", "needs a number"},
+		{"oneof=", "needs values"},
+		{"uuid", "unsupported validate rule"},
+		{"required,,email", "unsupported validate rule"},
+	}
+	for _, tt := range tests {
+		err := CheckRules(tt.rules)
+		switch {
+		case tt.wantErr == "" && err != nil:
+			t.Errorf("CheckRules(%q) = %v, want nil", tt.rules, err)
+		case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+			t.Errorf("CheckRules(%q) = %v, want %q", tt.rules, err, tt.wantErr)
+		}
+	}
+}
+
+func TestFieldChecks(t *testing.T) {
+	tests := []struct {
+		name    string
+		typ     string
+		rules   string
+		want    []tmpl.ValidatorCheck
+		imports []string
+	}{
+		{
+			name: "required string",
+			typ:  "string", rules: "required",
+			want: []tmpl.ValidatorCheck{{Cond: `r.F == ""`, Msg: "f is required"}},
+		},
+		{
+			name: "string length counts runes",
+			typ:  "string", rules: "min=3,max=50",
+			want: []tmpl.ValidatorCheck{
+				{Cond: "utf8.RuneCountInString(r.F) < 3", Msg: "f must be at least 3 characters"},
+				{Cond: "utf8.RuneCountInString(r.F) > 50", Msg: "f must be at most 50 characters"},
+			},
+			imports: []string{"unicode/utf8"},
+		},
+		{
+			name: "number range",
+			typ:  "int64", rules: "min=1",
+			want: []tmpl.ValidatorCheck{{Cond: "r.F < 1", Msg: "f must be at least 1"}},
+		},
+		{
+			name: "slice length",
+			typ:  "[]string", rules: "required,max=5",
+			want: []tmpl.ValidatorCheck{
+				{Cond: "len(r.F) == 0", Msg: "f is required"},
+				{Cond: "len(r.F) > 5", Msg: "f must be at most 5 items"},
+			},
+		},
+		{
+			name: "email",
+			typ:  "string", rules: "email",
+			want:    []tmpl.ValidatorCheck{{Cond: "!isEmail(r.F)", Msg: "f must be a valid email address"}},
+			imports: []string{"net/mail"},
+		},
+		{
+			name: "oneof quotes strings",
+			typ:  "string", rules: "oneof=draft sent",
+			want: []tmpl.ValidatorCheck{{Cond: `r.F != "draft" && r.F != "sent"`, Msg: "f must be one of draft sent"}},
+		},
+		{
+			name: "oneof numbers",
+			typ:  "int", rules: "oneof=1 2",
+			want: []tmpl.ValidatorCheck{{Cond: "r.F != 1 && r.F != 2", Msg: "f must be one of 1 2"}},
+		},
+		{
+			name: "omitempty guards the other rules",
+			typ:  "string", rules: "omitempty,email",
+			want:    []tmpl.ValidatorCheck{{Cond: `!(r.F == "") && (!isEmail(r.F))`, Msg: "f must be a valid email address"}},
+			imports: []string{"net/mail"},
+		},
+		{
+			name: "pointer: required is nil, others dereference",
+			typ:  "*int", rules: "required,max=9",
+			want: []tmpl.ValidatorCheck{
+				{Cond: "r.F == nil", Msg: "f is required"},
+				{Cond: "r.F != nil && (*r.F > 9)", Msg: "f must be at most 9"},
+			},
+		},
+		{
+			name: "required time",
+			typ:  "time.Time", rules: "required",
+			want: []tmpl.ValidatorCheck{{Cond: "r.F.IsZero()", Msg: "f is required"}},
+		},
+		{
+			name: "rules that do not fit the type are skipped",
+			typ:  "bool", rules: "required,email,max=1",
+			want: []tmpl.ValidatorCheck{{Cond: "!r.F", Msg: "f is required"}},
+		},
+		{
+			name: "unknown types get nothing",
+			typ:  "uuid.UUID", rules: "required",
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			imports := map[string]bool{}
+			got := fieldChecks("r.F", tt.typ, "f", tt.rules, imports)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("checks =\n %q\nwant\n %q", got, tt.want)
+			}
+			var gotImports []string
+			for imp := range imports {
+				gotImports = append(gotImports, imp)
+			}
+			if !reflect.DeepEqual(gotImports, tt.imports) {
+				t.Errorf("imports = %v, want %v", gotImports, tt.imports)
+			}
+		})
+	}
+}
+
+func TestFieldLabel(t *testing.T) {
+	tests := []struct {
+		tag  reflect.StructTag
+		want string
+	}{
+		{`json:"email,omitempty" validate:"email"`, "email"},
+		{`query:"page"`, "page"},
+		{`json:"-" form:"avatar"`, "avatar"},
+		{`param:"id" json:"user_id"`, "user_id"}, // json wins
+		{`validate:"required"`, "Email"},
+	}
+	for _, tt := range tests {
+		if got := fieldLabel("Email", tt.tag); got != tt.want {
+			t.Errorf("fieldLabel(%s) = %q, want %q", tt.tag, got, tt.want)
+		}
+	}
+}
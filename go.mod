@@ -4,9 +4,10 @@ go 1.23.0
 
 toolchain go1.23.12
 
-require golang.org/x/tools v0.36.0
-
 require (
-	golang.org/x/mod v0.27.0 // indirect
-	golang.org/x/sync v0.16.0 // indirect
+	golang.org/x/mod v0.27.0
+	golang.org/x/tools v0.36.0
+	gopkg.in/yaml.v3 v3.0.1
 )
+
+require golang.org/x/sync v0.16.0 // indirect
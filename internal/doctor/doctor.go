@@ -0,0 +1,313 @@
+// Package doctor validates that a project still has every anchor the
+// generators rely on, so drift is caught before a half-applied scaffold.
+package doctor
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"golang.org/x/mod/modfile"
+
+	"github.com/AndreeJait/ntaps/internal/paths"
+	"github.com/AndreeJait/ntaps/internal/tmpl"
+	"github.com/AndreeJait/ntaps/internal/util"
+)
+
+type Severity string
+
+const (
+	Error   Severity = "error"
+	Warning Severity = "warning"
+)
+
+// Problem is one finding. Line is 0 when the problem is about the file as
+// a whole (e.g. it is missing).
+type Problem struct {
+	File     string
+	Line     int
+	Severity Severity
+	Message  string
+	Fix      string
+}
+
+func (p Problem) String() string {
+	loc := p.File
+	if p.Line > 0 {
+		loc = fmt.Sprintf("%s:%d", p.File, p.Line)
+	}
+	icon := "❌"
+	if p.Severity == Warning {
+		icon = "⚠️ "
+	}
+	return fmt.Sprintf("%s %s: %s\n    fix: %s", icon, loc, p.Message, p.Fix)
+}
+
+type checker struct {
+	problems []Problem
+}
+
+func (c *checker) add(sev Severity, file string, line int, msg, fix string) {
+	c.problems = append(c.problems, Problem{File: file, Line: line, Severity: sev, Message: msg, Fix: fix})
+}
+
+// Run executes every check and returns the problems found, errors first.
+func Run() []Problem {
+	c := &checker{}
+	c.checkModule()
+	c.checkTemplates()
+	c.checkInfraUsecase()
+	c.checkInfraRepository()
+	c.checkInfraHandler()
+	c.checkUsecaseDI()
+	c.checkRepositoryDI()
+	c.checkUsecasePkgs()
+	c.checkHandlerPkgs()
+	c.checkRepoPkgs()
+
+	sort.SliceStable(c.problems, func(i, j int) bool {
+		return c.problems[i].Severity == Error && c.problems[j].Severity != Error
+	})
+	return c.problems
+}
+
+func (c *checker) checkModule() {
+	raw, err := os.ReadFile("go.mod")
+	if err != nil {
+		c.add(Error, "go.mod", 0, "go.mod not found; module detection falls back to "+util.ModulePathGuess(),
+			"run ntaps from the module root or `ntaps init --module=<path>`")
+		return
+	}
+	f, err := modfile.ParseLax("go.mod", raw, nil)
+	if err != nil || f.Module == nil {
+		c.add(Error, "go.mod", 0, "go.mod has no parsable module directive", "add `module <path>` as the first line")
+		return
+	}
+	if guess := util.ModulePathGuess(); guess != f.Module.Mod.Path {
+		c.add(Error, "go.mod", f.Module.Syntax.Start.Line,
+			fmt.Sprintf("module is %q but ntaps detects %q (it only reads the first line)", f.Module.Mod.Path, guess),
+			"move the `module` directive to line 1 of go.mod")
+	}
+}
+
+func (c *checker) checkTemplates() {
+	if err := tmpl.Check(); err != nil {
+		c.add(Error, paths.TemplatesDir, 0, err.Error(), "fix the template syntax or delete the override to use the built-in one")
+	}
+}
+
+func (c *checker) checkInfraUsecase() {
+	path := paths.InfraInitUsecasePath
+	src, ok := c.read(path, Error, "run `ntaps init` or set paths.infraInitUsecase in .ntaps.yaml")
+	if !ok {
+		return
+	}
+	c.requireInitFunc(path, src, paths.UsecaseInitMarker, "initUseCase", "markers.initUseCase")
+}
+
+func (c *checker) checkInfraRepository() {
+	path := paths.InfraRepoInitPath
+	src, ok := c.read(path, Error, "run `ntaps init` or set paths.infraInitRepository in .ntaps.yaml")
+	if !ok {
+		return
+	}
+	c.requireInitFunc(path, src, paths.RepositoryInitMarker, "initRepository", "markers.initRepository")
+}
+
+func (c *checker) checkInfraHandler() {
+	path := paths.HandlerInfraInitPath
+	src, ok := c.read(path, Error, "run `ntaps init` or set paths.infraInitHandler in .ntaps.yaml")
+	if !ok {
+		return
+	}
+	line := c.requireMarker(path, src, paths.HandlersSliceMarker, "handlers slice",
+		"declare `"+paths.HandlersSliceMarker+" }` or set markers.handlersSlice in .ntaps.yaml")
+	if line == 0 {
+		return
+	}
+	loc := util.MarkerRegexp(paths.HandlersSliceMarker).FindStringIndex(src)
+	if !strings.Contains(src[loc[1]:], "}") {
+		c.add(Error, path, line, "handlers slice has no closing '}'", "close the slice literal")
+	}
+}
+
+func (c *checker) checkUsecaseDI() {
+	path := paths.UsecaseDIPath
+	src, ok := c.read(path, Warning, "it will be created on the first create-usecase, or run `ntaps init`")
+	if !ok {
+		return
+	}
+	c.requireMarker(path, src, paths.UsecaseStructMarker, "aggregated UseCase struct",
+		"declare `"+paths.UsecaseStructMarker+" }` or set markers.usecaseStruct in .ntaps.yaml")
+}
+
+func (c *checker) checkRepositoryDI() {
+	path := paths.PgDiPath
+	src, ok := c.read(path, Warning, "it will be created on the first create-repository, or run `ntaps init`")
+	if !ok {
+		return
+	}
+	c.requireMarker(path, src, paths.RepositoryStructMarker, "aggregated Repository struct",
+		"declare `"+paths.RepositoryStructMarker+" }` or set markers.repositoryStruct in .ntaps.yaml")
+}
+
+// checkUsecasePkgs verifies each usecase package is shaped the way
+// create-usecase / add-repo-to-usecase edit it, and is wired into DI.
+func (c *checker) checkUsecasePkgs() {
+	diSrc, _ := os.ReadFile(paths.UsecaseDIPath)
+	initSrc, _ := os.ReadFile(paths.InfraInitUsecasePath)
+
+	for _, pkg := range subdirs(paths.RootUsecaseDir) {
+		dir := filepath.Join(paths.RootUsecaseDir, pkg)
+		field := util.ToPascalCase(pkg) + "Uc"
+
+		if port, err := os.ReadFile(filepath.Join(dir, "port.go")); err == nil && !strings.Contains(string(port), "type UseCase interface {") {
+			c.add(Error, filepath.Join(dir, "port.go"), 0, "`type UseCase interface {` not found",
+				"keep the port interface named UseCase so methods can be appended")
+		}
+		if impl, err := os.ReadFile(filepath.Join(dir, "usecase.go")); err == nil {
+			s := string(impl)
+			if !strings.Contains(s, "type useCase struct {") {
+				c.add(Error, filepath.Join(dir, "usecase.go"), 0, "`type useCase struct {` not found",
+					"keep the implementation struct named useCase so repos can be injected")
+			}
+			if !regexp.MustCompile(`func\s+NewUseCase\(`).MatchString(s) {
+				c.add(Error, filepath.Join(dir, "usecase.go"), 0, "NewUseCase constructor not found",
+					"keep `func NewUseCase(...) UseCase` so repo arguments can be appended")
+			}
+		}
+		if diSrc != nil && !util.HasField(string(diSrc), field, pkg+".UseCase") {
+			c.add(Warning, paths.UsecaseDIPath, 0, fmt.Sprintf("usecase %s is not a field of the UseCase struct", pkg),
+				fmt.Sprintf("add `%s %s.UseCase` or re-run `ntaps create-usecase --pkg=%s`", field, pkg, pkg))
+		}
+		assignRe := regexp.MustCompile(fmt.Sprintf(`%s\.uc\.%s\s*=`, regexp.QuoteMeta(paths.WireReceiver), field))
+		if initSrc != nil && !assignRe.Match(initSrc) {
+			hint, line := "", 0
+			if loc := regexp.MustCompile(`\w+\.\w+\.` + field + `\s*=`).FindIndex(initSrc); loc != nil {
+				hint, line = " (an assignment exists but not through `"+paths.WireReceiver+".uc`)", lineOf(string(initSrc), loc[0])
+			}
+			c.add(Error, paths.InfraInitUsecasePath, line,
+				fmt.Sprintf("`%s.uc.%s = ...` assignment not found%s", paths.WireReceiver, field, hint),
+				fmt.Sprintf("assign `%s.uc.%s = %s.NewUseCase(...)` in initUseCase, or set markers.receiver", paths.WireReceiver, field, pkg))
+		}
+	}
+}
+
+func (c *checker) checkHandlerPkgs() {
+	sliceSrc, _ := os.ReadFile(paths.HandlerInfraInitPath)
+
+	for _, pkg := range subdirs(paths.HandlerRootHTTPDir) {
+		path := filepath.Join(paths.HandlerRootHTTPDir, pkg, paths.HandlerPkgFileName)
+		raw, err := os.ReadFile(path)
+		if err != nil {
+			continue // not a generated handler pkg (e.g. common/)
+		}
+		src := string(raw)
+		ctor := fmt.Sprintf("func New%sHandler(", util.ToPascalCase(pkg))
+		if !strings.Contains(src, ctor) {
+			continue
+		}
+		if c.requireMarker(path, src, paths.HandleFuncMarker, "Handle() method",
+			"keep `"+paths.HandleFuncMarker+"` so routes can be registered") == 0 {
+			continue
+		}
+		if !strings.Contains(src, paths.RoutesMarker) {
+			c.add(Error, path, lineOf(src, util.MarkerRegexp(paths.HandleFuncMarker).FindStringIndex(src)[0]),
+				fmt.Sprintf("routes marker %q missing; new routes would be appended at the last '}' of the file", paths.RoutesMarker),
+				fmt.Sprintf("put `%s` as the last line inside Handle()", paths.RoutesMarker))
+		}
+		if sliceSrc != nil && !strings.Contains(string(sliceSrc), fmt.Sprintf("%s.New%sHandler(", pkg, util.ToPascalCase(pkg))) {
+			c.add(Warning, paths.HandlerInfraInitPath, 0, fmt.Sprintf("handler %s is not registered in the handlers slice", pkg),
+				fmt.Sprintf("run `ntaps create-handler --pkg=%s`", pkg))
+		}
+	}
+}
+
+func (c *checker) checkRepoPkgs() {
+	diSrc, _ := os.ReadFile(paths.PgDiPath)
+	initSrc, _ := os.ReadFile(paths.InfraRepoInitPath)
+
+	for _, pkg := range subdirs(paths.RepoPgPath) {
+		path := filepath.Join(paths.RepoPgPath, pkg, "impl.go")
+		raw, err := os.ReadFile(path)
+		if err != nil {
+			continue // e.g. the sqlc package
+		}
+		src := string(raw)
+		if !strings.Contains(src, "type Repository struct") {
+			c.add(Error, path, 0, "`type Repository struct` not found", "keep the repository struct named Repository")
+		}
+		field := util.ToPascalCase(pkg) + "Repo"
+		if diSrc != nil && !util.HasField(string(diSrc), field, "*"+pkg+".Repository") {
+			c.add(Warning, paths.PgDiPath, 0, fmt.Sprintf("repository %s is not a field of the Repository struct", pkg),
+				fmt.Sprintf("add `%s *%s.Repository` or re-run create-repository --pkg=%s", field, pkg, pkg))
+		}
+		assign := fmt.Sprintf("%s.repo.%s =", paths.WireReceiver, field)
+		if initSrc != nil && !regexp.MustCompile(regexp.QuoteMeta(paths.WireReceiver)+`\.repo\.`+field+`\s*=`).Match(initSrc) {
+			c.add(Warning, paths.InfraRepoInitPath, 0, fmt.Sprintf("`%s ...` not found", assign),
+				fmt.Sprintf("assign it in initRepository or re-run create-repository --pkg=%s", pkg))
+		}
+	}
+}
+
+// requireInitFunc checks an init function marker. When the marker is gone
+// but the function still exists (e.g. its receiver was renamed), it points
+// at the function and suggests the matching .ntaps.yaml markers.
+func (c *checker) requireInitFunc(path, src, marker, fn, key string) {
+	if util.MarkerRegexp(marker).MatchString(src) {
+		return
+	}
+	re := regexp.MustCompile(`func\s*\(\s*(\w+)\s+(\*?\w+)\s*\)\s*` + fn + `\(`)
+	m := re.FindStringSubmatchIndex(src)
+	if m == nil {
+		c.add(Error, path, 0, fmt.Sprintf("%s function not found (looking for %q)", fn, marker),
+			fmt.Sprintf("add `%s) { }` or set %s in .ntaps.yaml", marker, key))
+		return
+	}
+	recv, typ := src[m[2]:m[3]], src[m[4]:m[5]]
+	fix := fmt.Sprintf("set %s: \"func (%s %s) %s(\"", key, recv, typ, fn)
+	if recv != paths.WireReceiver {
+		fix += " and markers.receiver: " + recv
+	}
+	c.add(Error, path, lineOf(src, m[0]), fmt.Sprintf("%s does not match marker %q", fn, marker), fix+" in .ntaps.yaml")
+}
+
+func (c *checker) read(path string, sev Severity, fix string) (string, bool) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		c.add(sev, path, 0, "file not found", fix)
+		return "", false
+	}
+	return string(raw), true
+}
+
+// requireMarker reports a missing marker and returns its 1-based line, or 0.
+func (c *checker) requireMarker(path, src, marker, what, fix string) int {
+	loc := util.MarkerRegexp(marker).FindStringIndex(src)
+	if loc == nil {
+		c.add(Error, path, 0, fmt.Sprintf("%s not found (looking for %q)", what, marker), fix)
+		return 0
+	}
+	return lineOf(src, loc[0])
+}
+
+func lineOf(src string, offset int) int {
+	return strings.Count(src[:offset], "\n") + 1
+}
+
+func subdirs(dir string) []string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	var out []string
+	for _, e := range entries {
+		if e.IsDir() {
+			out = append(out, e.Name())
+		}
+	}
+	return out
+}
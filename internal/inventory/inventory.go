@@ -0,0 +1,433 @@
+// Package inventory parses a project and reports the service surface ntaps
+// generated: usecases, routes, repositories, outbound ports and which repos
+// are injected into which usecases. It only reads files.
+package inventory
+
+import (
+	"bytes"
+	"go/ast"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/AndreeJait/ntaps/internal/paths"
+	"github.com/AndreeJait/ntaps/internal/util"
+)
+
+type Inventory struct {
+	Usecases     []Usecase    `json:"usecases"`
+	Handlers     []Handler    `json:"handlers"`
+	Repositories []Repository `json:"repositories"`
+	Outbounds    []Outbound   `json:"outbounds"`
+}
+
+type Method struct {
+	Name      string `json:"name"`
+	Signature string `json:"signature"`
+}
+
+type Usecase struct {
+	Pkg     string   `json:"pkg"`
+	Methods []Method `json:"methods"`
+	// Repos lists the repository pkgs passed to NewUseCase in DI.
+	Repos []string `json:"repos,omitempty"`
+}
+
+type Handler struct {
+	Pkg    string  `json:"pkg"`
+	Routes []Route `json:"routes"`
+}
+
+type Route struct {
+	Group    string `json:"group"` // public | internal | private
+	Verb     string `json:"verb"`
+	Path     string `json:"path"` // full path including the group prefix
+	Method   string `json:"method"`
+	UcPkg    string `json:"ucPkg,omitempty"`
+	UcMethod string `json:"ucMethod,omitempty"`
+}
+
+type Repository struct {
+	Pkg     string   `json:"pkg"`
+	Methods []Method `json:"methods"`
+}
+
+type Outbound struct {
+	Pkg     string   `json:"pkg"`
+	Iface   string   `json:"iface"`
+	Methods []Method `json:"methods"`
+}
+
+// Collect walks the configured layout and builds the inventory.
+func Collect() *Inventory {
+	inv := &Inventory{Usecases: []Usecase{}, Handlers: []Handler{}, Repositories: []Repository{}, Outbounds: []Outbound{}}
+
+	ucFields := fieldPkgs(paths.UsecaseDIPath, paths.UsecaseStructMarker)
+	repoFields := fieldPkgs(paths.PgDiPath, paths.RepositoryStructMarker)
+	injected := injectedRepos(repoFields)
+
+	for _, pkg := range subdirs(paths.RootUsecaseDir) {
+		f, err := parseFile(filepath.Join(paths.RootUsecaseDir, pkg, "port.go"))
+		if err != nil {
+			continue
+		}
+		inv.Usecases = append(inv.Usecases, Usecase{
+			Pkg:     pkg,
+			Methods: interfaceMethods(f, "UseCase"),
+			Repos:   injected[pkg],
+		})
+	}
+
+	for _, pkg := range subdirs(paths.HandlerRootHTTPDir) {
+		f, err := parseFile(filepath.Join(paths.HandlerRootHTTPDir, pkg, paths.HandlerPkgFileName))
+		if err != nil || findMethod(f, "Handle") == nil {
+			continue
+		}
+		inv.Handlers = append(inv.Handlers, Handler{Pkg: pkg, Routes: routes(f, ucFields)})
+	}
+
+	for _, pkg := range subdirs(paths.RepoPgPath) {
+		files := parseDir(filepath.Join(paths.RepoPgPath, pkg))
+		if !declaresType(files, "Repository") {
+			continue // e.g. the sqlc package
+		}
+		methods := []Method{}
+		for _, f := range files {
+			methods = append(methods, receiverMethods(f, "Repository")...)
+		}
+		inv.Repositories = append(inv.Repositories, Repository{Pkg: pkg, Methods: methods})
+	}
+
+	repoRoot := filepath.Clean(paths.RepoRootPath)
+	for _, pkg := range subdirs(paths.OutboundRootPath) {
+		dir := filepath.Join(paths.OutboundRootPath, pkg)
+		if filepath.Clean(dir) == repoRoot {
+			continue
+		}
+		f, err := parseFile(filepath.Join(dir, "port.go"))
+		if err != nil {
+			continue
+		}
+		for _, name := range interfaceNames(f) {
+			inv.Outbounds = append(inv.Outbounds, Outbound{Pkg: pkg, Iface: name, Methods: interfaceMethods(f, name)})
+		}
+	}
+
+	return inv
+}
+
+// routes reads the group.VERB("/path", h.method) calls in Handle() and
+// follows each handler method to the h.uc.<Field>.<Method> call it makes.
+func routes(f *ast.File, ucFields map[string]string) []Route {
+	handle := findMethod(f, "Handle")
+
+	// groupPrivate := h.route.Group("/send")
+	prefixes := map[string]string{}
+	ast.Inspect(handle.Body, func(n ast.Node) bool {
+		as, ok := n.(*ast.AssignStmt)
+		if !ok || len(as.Lhs) != 1 || len(as.Rhs) != 1 {
+			return true
+		}
+		id, ok := as.Lhs[0].(*ast.Ident)
+		call, ok2 := as.Rhs[0].(*ast.CallExpr)
+		if !ok || !ok2 || len(call.Args) == 0 {
+			return true
+		}
+		if sel, ok := call.Fun.(*ast.SelectorExpr); ok && sel.Sel.Name == "Group" {
+			prefixes[id.Name] = stringLit(call.Args[0])
+		}
+		return true
+	})
+
+	out := []Route{}
+	ast.Inspect(handle.Body, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok || len(call.Args) < 2 {
+			return true
+		}
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok || !isVerb(sel.Sel.Name) {
+			return true
+		}
+		group, ok := sel.X.(*ast.Ident)
+		target, ok2 := call.Args[1].(*ast.SelectorExpr)
+		if !ok || !ok2 {
+			return true
+		}
+		r := Route{
+			Group:  groupKind(group.Name),
+			Verb:   sel.Sel.Name,
+			Path:   prefixes[group.Name] + stringLit(call.Args[0]),
+			Method: target.Sel.Name,
+		}
+		if fd := findMethod(f, r.Method); fd != nil {
+			r.UcPkg, r.UcMethod = ucCall(fd, ucFields)
+		}
+		out = append(out, r)
+		return true
+	})
+	return out
+}
+
+// ucCall finds h.uc.<Field>.<Method>(...) inside a handler method.
+func ucCall(fd *ast.FuncDecl, ucFields map[string]string) (pkg, method string) {
+	ast.Inspect(fd.Body, func(n ast.Node) bool {
+		if method != "" {
+			return false
+		}
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		m, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		field, ok := m.X.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		if uc, ok := field.X.(*ast.SelectorExpr); ok && uc.Sel.Name == "uc" {
+			pkg, method = ucFields[field.Sel.Name], m.Sel.Name
+			if pkg == "" {
+				pkg = strings.ToLower(strings.TrimSuffix(field.Sel.Name, "Uc"))
+			}
+		}
+		return true
+	})
+	return pkg, method
+}
+
+// injectedRepos maps usecase pkg -> repo pkgs from the
+// <recv>.uc.<X>Uc = <pkg>.NewUseCase(..., <recv>.repo.<Y>Repo) lines.
+func injectedRepos(repoFields map[string]string) map[string][]string {
+	out := map[string][]string{}
+	f, err := parseFile(paths.InfraInitUsecasePath)
+	if err != nil {
+		return out
+	}
+	ast.Inspect(f, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		fn, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok || fn.Sel.Name != "NewUseCase" {
+			return true
+		}
+		pkg, ok := fn.X.(*ast.Ident)
+		if !ok {
+			return true
+		}
+		for _, a := range call.Args {
+			sel, ok := a.(*ast.SelectorExpr)
+			if !ok || !strings.HasSuffix(sel.Sel.Name, "Repo") {
+				continue
+			}
+			if repo, ok := sel.X.(*ast.SelectorExpr); ok && repo.Sel.Name == "repo" {
+				name := repoFields[sel.Sel.Name]
+				if name == "" {
+					name = strings.ToLower(strings.TrimSuffix(sel.Sel.Name, "Repo"))
+				}
+				out[pkg.Name] = append(out[pkg.Name], name)
+			}
+		}
+		return true
+	})
+	return out
+}
+
+// fieldPkgs maps field name -> package of its type for the aggregated
+// struct in a DI file, e.g. SendUc send.UseCase -> "SendUc": "send".
+func fieldPkgs(path, marker string) map[string]string {
+	out := map[string]string{}
+	f, err := parseFile(path)
+	if err != nil {
+		return out
+	}
+	name := strings.Fields(marker)
+	if len(name) < 2 {
+		return out
+	}
+	ast.Inspect(f, func(n ast.Node) bool {
+		ts, ok := n.(*ast.TypeSpec)
+		if !ok || ts.Name.Name != name[1] {
+			return true
+		}
+		st, ok := ts.Type.(*ast.StructType)
+		if !ok {
+			return false
+		}
+		for _, fld := range st.Fields.List {
+			typ := fld.Type
+			if star, ok := typ.(*ast.StarExpr); ok {
+				typ = star.X
+			}
+			sel, ok := typ.(*ast.SelectorExpr)
+			if !ok {
+				continue
+			}
+			if id, ok := sel.X.(*ast.Ident); ok {
+				for _, n := range fld.Names {
+					out[n.Name] = id.Name
+				}
+			}
+		}
+		return false
+	})
+	return out
+}
+
+func interfaceMethods(f *ast.File, iface string) []Method {
+	out := []Method{}
+	ast.Inspect(f, func(n ast.Node) bool {
+		ts, ok := n.(*ast.TypeSpec)
+		if !ok || ts.Name.Name != iface {
+			return true
+		}
+		it, ok := ts.Type.(*ast.InterfaceType)
+		if !ok {
+			return false
+		}
+		for _, m := range it.Methods.List {
+			ft, ok := m.Type.(*ast.FuncType)
+			if !ok || len(m.Names) == 0 {
+				continue
+			}
+			out = append(out, Method{Name: m.Names[0].Name, Signature: m.Names[0].Name + funcSig(ft)})
+		}
+		return false
+	})
+	return out
+}
+
+func interfaceNames(f *ast.File) []string {
+	var out []string
+	for _, d := range f.Decls {
+		gd, ok := d.(*ast.GenDecl)
+		if !ok || gd.Tok != token.TYPE {
+			continue
+		}
+		for _, s := range gd.Specs {
+			ts := s.(*ast.TypeSpec)
+			if _, ok := ts.Type.(*ast.InterfaceType); ok && ts.Name.IsExported() {
+				out = append(out, ts.Name.Name)
+			}
+		}
+	}
+	return out
+}
+
+func receiverMethods(f *ast.File, recv string) []Method {
+	out := []Method{}
+	for _, d := range f.Decls {
+		fd, ok := d.(*ast.FuncDecl)
+		if !ok || fd.Recv == nil || len(fd.Recv.List) == 0 || !fd.Name.IsExported() {
+			continue
+		}
+		typ := fd.Recv.List[0].Type
+		if star, ok := typ.(*ast.StarExpr); ok {
+			typ = star.X
+		}
+		if id, ok := typ.(*ast.Ident); ok && id.Name == recv {
+			out = append(out, Method{Name: fd.Name.Name, Signature: fd.Name.Name + funcSig(fd.Type)})
+		}
+	}
+	return out
+}
+
+func declaresType(files []*ast.File, name string) bool {
+	for _, f := range files {
+		if obj := f.Scope.Lookup(name); obj != nil && obj.Kind == ast.Typ {
+			return true
+		}
+	}
+	return false
+}
+
+func findMethod(f *ast.File, name string) *ast.FuncDecl {
+	for _, d := range f.Decls {
+		if fd, ok := d.(*ast.FuncDecl); ok && fd.Recv != nil && fd.Name.Name == name && fd.Body != nil {
+			return fd
+		}
+	}
+	return nil
+}
+
+// funcSig prints a func type without the leading "func", e.g.
+// "(ctx context.Context, req XRequest) (XResponse, error)".
+func funcSig(ft *ast.FuncType) string {
+	var b bytes.Buffer
+	_ = printer.Fprint(&b, token.NewFileSet(), ft)
+	return strings.TrimPrefix(b.String(), "func")
+}
+
+func stringLit(e ast.Expr) string {
+	if lit, ok := e.(*ast.BasicLit); ok && lit.Kind == token.STRING {
+		if s, err := strconv.Unquote(lit.Value); err == nil {
+			return s
+		}
+	}
+	return ""
+}
+
+func isVerb(s string) bool {
+	switch s {
+	case "GET", "POST", "PUT", "PATCH", "DELETE", "HEAD", "OPTIONS", "CONNECT", "TRACE", "Any":
+		return true
+	}
+	return false
+}
+
+// groupKind turns groupPrivate into "private"; unknown names are kept.
+func groupKind(name string) string {
+	if k := strings.TrimPrefix(name, "group"); k != name && k != "" {
+		return strings.ToLower(k)
+	}
+	return name
+}
+
+func parseFile(path string) (*ast.File, error) {
+	src, err := util.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return parser.ParseFile(token.NewFileSet(), path, src, 0)
+}
+
+func parseDir(dir string) []*ast.File {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	var out []*ast.File
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".go") || strings.HasSuffix(e.Name(), "_test.go") {
+			continue
+		}
+		if f, err := parseFile(filepath.Join(dir, e.Name())); err == nil {
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+func subdirs(dir string) []string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	var out []string
+	for _, e := range entries {
+		if e.IsDir() {
+			out = append(out, e.Name())
+		}
+	}
+	sort.Strings(out)
+	return out
+}
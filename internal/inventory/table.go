@@ -0,0 +1,66 @@
+package inventory
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+// WriteTable prints the inventory as aligned sections for humans.
+func (inv *Inventory) WriteTable(w io.Writer) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintf(tw, "USECASES\nPKG\tMETHOD\tSIGNATURE\tREPOS\n")
+	for _, uc := range inv.Usecases {
+		repos := strings.Join(uc.Repos, ",")
+		if len(uc.Methods) == 0 {
+			fmt.Fprintf(tw, "%s\t-\t-\t%s\n", uc.Pkg, repos)
+		}
+		for _, m := range uc.Methods {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", uc.Pkg, m.Name, m.Signature, repos)
+		}
+	}
+
+	section(tw, "ROUTES", "HANDLER\tGROUP\tVERB\tPATH\tMETHOD\tUSECASE")
+	for _, h := range inv.Handlers {
+		if len(h.Routes) == 0 {
+			fmt.Fprintf(tw, "%s\t-\t-\t-\t-\t-\n", h.Pkg)
+		}
+		for _, r := range h.Routes {
+			uc := "-"
+			if r.UcMethod != "" {
+				uc = r.UcPkg + "." + r.UcMethod
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", h.Pkg, r.Group, r.Verb, r.Path, r.Method, uc)
+		}
+	}
+
+	section(tw, "REPOSITORIES", "PKG\tMETHOD\tSIGNATURE")
+	for _, r := range inv.Repositories {
+		if len(r.Methods) == 0 {
+			fmt.Fprintf(tw, "%s\t-\t-\n", r.Pkg)
+		}
+		for _, m := range r.Methods {
+			fmt.Fprintf(tw, "%s\t%s\t%s\n", r.Pkg, m.Name, m.Signature)
+		}
+	}
+
+	section(tw, "OUTBOUNDS", "PKG\tIFACE\tMETHOD\tSIGNATURE")
+	for _, o := range inv.Outbounds {
+		if len(o.Methods) == 0 {
+			fmt.Fprintf(tw, "%s\t%s\t-\t-\n", o.Pkg, o.Iface)
+		}
+		for _, m := range o.Methods {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", o.Pkg, o.Iface, m.Name, m.Signature)
+		}
+	}
+
+	_ = tw.Flush()
+}
+
+// section flushes the previous block so every section aligns on its own.
+func section(tw *tabwriter.Writer, title, header string) {
+	_ = tw.Flush()
+	fmt.Fprintf(tw, "\n%s\n%s\n", title, header)
+}
@@ -0,0 +1,242 @@
+// Package journal records what every command changed in
+// <JournalDir>/journal (one JSON line per run) and keeps the previous
+// content of each touched file in <JournalDir>/objects, so `ntaps undo`
+// can put it back.
+package journal
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/AndreeJait/ntaps/internal/paths"
+	"github.com/AndreeJait/ntaps/internal/util"
+)
+
+// File is one file touched by a run. Hashes are sha256 of the content;
+// an empty hash means the file did not exist.
+type File struct {
+	Path   string `json:"path"`
+	Before string `json:"before,omitempty"`
+	After  string `json:"after,omitempty"`
+}
+
+// Entry is one recorded run.
+type Entry struct {
+	ID    int       `json:"id"`
+	Time  time.Time `json:"time"`
+	Args  []string  `json:"args"`
+	Files []File    `json:"files"`
+}
+
+func (e Entry) String() string {
+	return fmt.Sprintf("#%d %s ntaps %s", e.ID, e.Time.Local().Format("2006-01-02 15:04:05"), strings.Join(e.Args, " "))
+}
+
+func journalPath() string { return filepath.Join(paths.JournalDir, "journal") }
+func objectPath(hash string) string {
+	return filepath.Join(paths.JournalDir, "objects", hash)
+}
+
+func hash(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+// Record appends the changes committed by this run. It is called after
+// util.Commit, so it writes straight to disk.
+func Record(args []string) error {
+	changes := util.StagedChanges()
+	if len(changes) == 0 {
+		return nil
+	}
+	entries, err := Entries()
+	if err != nil {
+		return err
+	}
+	e := Entry{ID: 1, Time: time.Now().UTC(), Args: args}
+	if len(entries) > 0 {
+		e.ID = entries[len(entries)-1].ID + 1
+	}
+
+	if err := os.MkdirAll(filepath.Join(paths.JournalDir, "objects"), 0o755); err != nil {
+		return err
+	}
+	for _, c := range changes {
+		f := File{Path: filepath.ToSlash(c.Path)}
+		if c.Existed {
+			f.Before = hash(c.Before)
+			if err := os.WriteFile(objectPath(f.Before), c.Before, 0o644); err != nil {
+				return err
+			}
+		}
+		if !c.Deleted {
+			f.After = hash(c.After)
+		}
+		e.Files = append(e.Files, f)
+	}
+
+	line, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	jf, err := os.OpenFile(journalPath(), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	defer jf.Close()
+	_, err = jf.Write(append(line, '\n'))
+	return err
+}
+
+// Entries returns the recorded runs, oldest first.
+func Entries() ([]Entry, error) {
+	raw, err := os.ReadFile(journalPath())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var out []Entry
+	sc := bufio.NewScanner(bytes.NewReader(raw))
+	sc.Buffer(make([]byte, 1024*1024), 16*1024*1024)
+	for sc.Scan() {
+		if len(bytes.TrimSpace(sc.Bytes())) == 0 {
+			continue
+		}
+		var e Entry
+		if err := json.Unmarshal(sc.Bytes(), &e); err != nil {
+			return nil, fmt.Errorf("%s is corrupt: %w", journalPath(), err)
+		}
+		out = append(out, e)
+	}
+	return out, sc.Err()
+}
+
+// Undo stages the restore of the last steps runs and returns them, newest
+// first. It refuses when a file no longer has the content the run left
+// behind, i.e. it was edited by hand since. The caller commits the staged
+// restores and then calls Drop.
+func Undo(steps int) ([]Entry, error) {
+	entries, err := Entries()
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("nothing to undo: %s is empty", journalPath())
+	}
+	if steps < 1 || steps > len(entries) {
+		return nil, fmt.Errorf("--steps must be between 1 and %d", len(entries))
+	}
+	undo := entries[len(entries)-steps:]
+
+	// walk newest -> oldest on a virtual view of the tree so a file touched
+	// by several runs is checked against each run's result in turn
+	state := map[string]string{}
+	current := func(p string) string {
+		if h, ok := state[p]; ok {
+			return h
+		}
+		b, err := os.ReadFile(filepath.FromSlash(p))
+		if err != nil {
+			return ""
+		}
+		return hash(b)
+	}
+	var edited []string
+	for i := len(undo) - 1; i >= 0; i-- {
+		for _, f := range undo[i].Files {
+			if current(f.Path) != f.After {
+				edited = append(edited, fmt.Sprintf("%s (changed after %s)", f.Path, undo[i]))
+			}
+			state[f.Path] = f.Before
+		}
+	}
+	if len(edited) > 0 {
+		return nil, fmt.Errorf("refusing to undo, files were edited since:\n    %s", strings.Join(edited, "\n    "))
+	}
+
+	for p, h := range state {
+		path := filepath.FromSlash(p)
+		if h == "" {
+			if err := util.RemoveFile(path); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		b, err := os.ReadFile(objectPath(h))
+		if err != nil {
+			return nil, fmt.Errorf("content of %s is missing from %s: %w", p, paths.JournalDir, err)
+		}
+		if err := util.WriteFile(path, string(b)); err != nil {
+			return nil, err
+		}
+	}
+
+	out := make([]Entry, 0, len(undo))
+	for i := len(undo) - 1; i >= 0; i-- {
+		out = append(out, undo[i])
+	}
+	return out, nil
+}
+
+// Drop removes the last steps runs from the journal, removes directories
+// those runs created that are now empty, and prunes objects no remaining
+// run refers to.
+func Drop(steps int) error {
+	entries, err := Entries()
+	if err != nil {
+		return err
+	}
+	if steps > len(entries) {
+		steps = len(entries)
+	}
+	keep := entries[:len(entries)-steps]
+
+	for _, e := range entries[len(keep):] {
+		for _, f := range e.Files {
+			if f.Before != "" {
+				continue
+			}
+			// os.Remove fails on non-empty dirs, which stops the walk up
+			for d := filepath.Dir(filepath.FromSlash(f.Path)); d != "." && os.Remove(d) == nil; d = filepath.Dir(d) {
+			}
+		}
+	}
+
+	var b bytes.Buffer
+	used := map[string]bool{}
+	for _, e := range keep {
+		line, err := json.Marshal(e)
+		if err != nil {
+			return err
+		}
+		b.Write(append(line, '\n'))
+		for _, f := range e.Files {
+			used[f.Before] = true
+		}
+	}
+	tmp := journalPath() + ".tmp"
+	if err := os.WriteFile(tmp, b.Bytes(), 0o644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, journalPath()); err != nil {
+		return err
+	}
+
+	objs, _ := os.ReadDir(filepath.Join(paths.JournalDir, "objects"))
+	for _, o := range objs {
+		if !used[o.Name()] {
+			_ = os.Remove(objectPath(o.Name()))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,180 @@
+package paths
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigFileName is discovered by walking up from the working directory.
+const ConfigFileName = ".ntaps.yaml"
+
+// Config mirrors .ntaps.yaml. Every field is optional; empty values keep
+// the defaults declared in paths.go.
+type Config struct {
+	Paths           ConfigPaths   `yaml:"paths"`
+	Markers         ConfigMarkers `yaml:"markers"`
+	MigrationFormat string        `yaml:"migrationFormat"` // migrate|goose
+}
+
+// ConfigPaths is the paths section of .ntaps.yaml.
+type ConfigPaths struct {
+	UsecaseDir          string `yaml:"usecaseDir"`
+	UsecaseDI           string `yaml:"usecaseDI"`
+	InfraInitUsecase    string `yaml:"infraInitUsecase"`
+	HandlerDir          string `yaml:"handlerDir"`
+	HandlerPkgFileName  string `yaml:"handlerPkgFileName"`
+	InfraInitHandler    string `yaml:"infraInitHandler"`
+	MiddlewareDir       string `yaml:"middlewareDir"`
+	RepoRootDir         string `yaml:"repoRootDir"`
+	RepoPostgresDir     string `yaml:"repoPostgresDir"`
+	SqlcDir             string `yaml:"sqlcDir"`
+	QueriesDir          string `yaml:"queriesDir"`
+	MigrationsDir       string `yaml:"migrationsDir"`
+	SqlcConfig          string `yaml:"sqlcConfig"`
+	RepoDI              string `yaml:"repoDI"`
+	InfraInitRepository string `yaml:"infraInitRepository"`
+	OutboundDir         string `yaml:"outboundDir"`
+	ConfigDir           string `yaml:"configDir"`
+	InfraDBDir          string `yaml:"infraDBDir"`
+	TemplatesDir        string `yaml:"templatesDir"`
+	JournalDir          string `yaml:"journalDir"`
+}
+
+// ConfigMarkers is the markers section of .ntaps.yaml.
+type ConfigMarkers struct {
+	InitUseCase      string `yaml:"initUseCase"`
+	InitRepository   string `yaml:"initRepository"`
+	HandlersSlice    string `yaml:"handlersSlice"`
+	HandleFunc       string `yaml:"handleFunc"`
+	Routes           string `yaml:"routes"`
+	UsecaseStruct    string `yaml:"usecaseStruct"`
+	RepositoryStruct string `yaml:"repositoryStruct"`
+	Receiver         string `yaml:"receiver"`
+}
+
+// Find walks up from dir looking for .ntaps.yaml and returns its path,
+// or "" when there is none.
+func Find(dir string) string {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return ""
+	}
+	for {
+		p := filepath.Join(dir, ConfigFileName)
+		if _, err := os.Stat(p); err == nil {
+			return p
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// WorkDir is the directory ntaps was started from, before Load switched
+// to the project root.
+var WorkDir string
+
+// UserPath resolves a path given on the command line against WorkDir, so
+// it still points where the user meant after Load changed directory.
+func UserPath(p string) string {
+	if p == "" || filepath.IsAbs(p) || WorkDir == "" {
+		return p
+	}
+	return filepath.Join(WorkDir, p)
+}
+
+// Load discovers .ntaps.yaml from the working directory, applies its
+// overrides and switches into the directory containing it, since every
+// configured path is relative to the project root. It returns the config
+// path, or "" when none was found (defaults stay in effect).
+func Load() (string, error) {
+	wd, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+	WorkDir = wd
+
+	p := Find(".")
+	if p == "" {
+		return "", nil
+	}
+
+	raw, err := os.ReadFile(p)
+	if err != nil {
+		return "", err
+	}
+	var c Config
+	dec := yaml.NewDecoder(bytes.NewReader(raw))
+	dec.KnownFields(true) // a mistyped key would otherwise be ignored
+	if err := dec.Decode(&c); err != nil && err != io.EOF {
+		return "", fmt.Errorf("parse %s: %w", p, err)
+	}
+	c.apply()
+
+	if err := os.Chdir(filepath.Dir(p)); err != nil {
+		return "", err
+	}
+	return p, nil
+}
+
+func (c Config) apply() {
+	set := func(dst *string, v string) {
+		if v != "" {
+			*dst = filepath.ToSlash(filepath.Clean(v))
+		}
+	}
+	setRaw := func(dst *string, v string) {
+		if v != "" {
+			*dst = v
+		}
+	}
+
+	p := c.Paths
+	set(&RootUsecaseDir, p.UsecaseDir)
+	set(&UsecaseDIPath, p.UsecaseDI)
+	set(&InfraInitUsecasePath, p.InfraInitUsecase)
+	set(&HandlerRootHTTPDir, p.HandlerDir)
+	set(&HandlerPkgFileName, p.HandlerPkgFileName)
+	set(&HandlerInfraInitPath, p.InfraInitHandler)
+	set(&MiddlewareDir, p.MiddlewareDir)
+	set(&RepoRootPath, p.RepoRootDir)
+	set(&RepoPgPath, p.RepoPostgresDir)
+	set(&SqlcDir, p.SqlcDir)
+	set(&QueriesDir, p.QueriesDir)
+	set(&MigrationsDir, p.MigrationsDir)
+	set(&SqlcConfigPath, p.SqlcConfig)
+	set(&PgDiPath, p.RepoDI)
+	set(&InfraRepoInitPath, p.InfraInitRepository)
+	set(&OutboundRootPath, p.OutboundDir)
+	set(&ConfigDir, p.ConfigDir)
+	set(&InfraDBDir, p.InfraDBDir)
+	set(&TemplatesDir, p.TemplatesDir)
+	set(&JournalDir, p.JournalDir)
+
+	// a usecase DI file that wasn't overridden follows the usecase dir
+	if p.UsecaseDir != "" && p.UsecaseDI == "" {
+		UsecaseDIPath = RootUsecaseDir + "/di.go"
+	}
+	if p.RepoPostgresDir != "" && p.SqlcDir == "" {
+		SqlcDir = RepoPgPath + "/sqlc"
+	}
+
+	setRaw(&MigrationFormat, c.MigrationFormat)
+
+	m := c.Markers
+	setRaw(&UsecaseInitMarker, m.InitUseCase)
+	setRaw(&RepositoryInitMarker, m.InitRepository)
+	setRaw(&HandlersSliceMarker, m.HandlersSlice)
+	setRaw(&HandleFuncMarker, m.HandleFunc)
+	setRaw(&RoutesMarker, m.Routes)
+	setRaw(&UsecaseStructMarker, m.UsecaseStruct)
+	setRaw(&RepositoryStructMarker, m.RepositoryStruct)
+	setRaw(&WireReceiver, m.Receiver)
+}
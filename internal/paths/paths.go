@@ -1,6 +1,9 @@
 package paths
 
-const (
+// Project layout ntaps generates into. These default to the
+// go-template-hexagonal layout and can be overridden per project with a
+// .ntaps.yaml file (see Load).
+var (
 	RootUsecaseDir       = "internal/usecase"
 	UsecaseDIPath        = "internal/usecase/di.go"
 	InfraInitUsecasePath = "internal/infrastructure/di/usecase.go"
@@ -8,11 +11,49 @@ const (
 	HandlerRootHTTPDir   = "internal/adapters/inbound/http"
 	HandlerPkgFileName   = "di.go"
 	HandlerInfraInitPath = "internal/infrastructure/di/handler.go"
+	MiddlewareDir        = "internal/adapters/inbound/http/common/middleware"
 
 	RepoRootPath      = "internal/adapters/outbound/db"
 	RepoPgPath        = "internal/adapters/outbound/db/postgres"
+	SqlcDir           = "internal/adapters/outbound/db/postgres/sqlc"
 	PgDiPath          = "internal/adapters/outbound/db/di.go"
 	InfraRepoInitPath = "internal/infrastructure/di/repository.go"
 
+	// QueriesDir holds the per-package sqlc query files (<pkg>.sql),
+	// MigrationsDir the schema sqlc reads, and SqlcConfigPath the sqlc.yaml
+	// that ties them together.
+	QueriesDir     = "db/queries"
+	MigrationsDir  = "db/migrations"
+	SqlcConfigPath = "sqlc.yaml"
+
+	// MigrationFormat is the default for create-migration: migrate
+	// (golang-migrate .up.sql/.down.sql pairs) or goose (one annotated file).
+	MigrationFormat = "migrate"
+
 	OutboundRootPath = "internal/adapters/outbound"
+
+	ConfigDir  = "internal/infrastructure/config"
+	InfraDBDir = "internal/infrastructure/db"
+
+	// TemplatesDir holds project overrides of the built-in code templates.
+	TemplatesDir = ".ntaps/templates"
+
+	// JournalDir holds the run journal and the file contents undo restores.
+	JournalDir = ".ntaps"
+)
+
+// Markers are the anchors ntaps looks for inside existing files before
+// inserting code. Whitespace inside a marker is matched loosely.
+var (
+	UsecaseInitMarker      = "func (s wire) initUseCase("
+	RepositoryInitMarker   = "func (s wire) initRepository("
+	HandlersSliceMarker    = "var handlers = []http.Handler{"
+	HandleFuncMarker       = "func (h *handler) Handle()"
+	RoutesMarker           = "// ntaps:routes"
+	UsecaseStructMarker    = "type UseCase struct {"
+	RepositoryStructMarker = "type Repository struct {"
+
+	// WireReceiver is the receiver name used in the generated DI lines,
+	// e.g. s.uc.<Pkg>Uc = ... inside initUseCase.
+	WireReceiver = "s"
 )
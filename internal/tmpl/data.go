@@ -0,0 +1,225 @@
+package tmpl
+
+// The types below are the data model handed to each template. Overrides in
+// .ntaps/templates receive exactly these fields.
+
+// HandlerPkg is rendered by handler_pkg.tmpl into <handlerDir>/<pkg>/di.go.
+type HandlerPkg struct {
+	Pkg              string // handler package, e.g. send
+	PkgPascal        string // Send (used for New<PkgPascal>Handler)
+	HTTPImport       string // import path of the http.Handler interface pkg
+	MiddlewareImport string
+	ConfigImport     string
+	UsecaseImport    string // import path of the aggregated usecase.UseCase
+	RoutesMarker     string // line new routes are inserted above
+}
+
+// HandlerMethod is rendered by handler_method.tmpl (swagger block + func).
+type HandlerMethod struct {
+	Method       string            // handler method, lowerCamel, e.g. getTransaction
+	UcPkg        string            // usecase package, e.g. send
+	UcField      string            // field on usecase.UseCase, e.g. SendUc
+	UcMethod     string            // usecase method, PascalCase
+	Summary      string            // humanized UcMethod, e.g. "Get Transaction"
+	Tag          string            // swagger tag
+	Verb         string            // upper-case HTTP verb
+	EndpointType string            // public|internal|private
+	Security     string            // BasicAuth, BearerAuth or "" for public
+	Route        string            // swagger @Router path, e.g. /send/transaction/{code}
+	PathParams   []string          // e.g. [code]
+	PathTypes    map[string]string // swagger type per path param, e.g. code: string
+	WithParam    bool              // usecase takes <UcMethod>Request
+	WithResponse bool              // usecase returns <UcMethod>Response
+	ParamIn      string            // where the Request is documented: body|query
+	RequestType  string            // e.g. send.GetTransactionRequest
+	ResponseType string            // e.g. send.GetTransactionResponse
+	Params       []HandlerParam    // the Request's query/header/cookie fields
+	Body         bool              // document the Request itself (json fields, or untyped)
+	Init         string            // Request literal fields from default tags, e.g. "Page: 1, Limit: 20"
+	Binds        []string          // statements after c.Bind: query/header/cookie binding, Validate()
+	Validate     bool              // the Request has a generated Validate()
+	Rules        string            // its rules for the 400 description, e.g. "email required,email"
+	Status       int               // success status, e.g. 201
+	StatusExpr   string            // Status as Go source, e.g. nethttp.StatusCreated
+	NoBody       bool              // Status cannot carry a body (204, 205): c.NoContent
+	Upload       *HandlerUpload    // multipart file passed to the usecase; nil for JSON
+	Download     string            // csv|file: stream the Response instead of JSON
+}
+
+// HandlerUpload is the multipart file of an upload handler.
+type HandlerUpload struct {
+	Name        string   // form field, e.g. file
+	Field       string   // Request field of the io.Reader, e.g. File (+Name, Size, Type)
+	MaxSize     int64    // bytes; 0 = no limit
+	MaxSizeText string   // MaxSize as written, e.g. 10MB
+	Types       []string // allowed sniffed content types; empty = any
+}
+
+// HandlerParam is one `@Param <Name> <In> <Type> <Required>` line.
+type HandlerParam struct {
+	Name     string // as sent, e.g. X-Request-ID
+	In       string // query|header|cookie|formData
+	Field    string // Go field on the Request, e.g. XRequestID
+	Type     string // swag type: string, integer, number, boolean, []string...
+	Required bool   // validate:"required"
+	Default  string // default tag, e.g. 20
+}
+
+// Usecase is rendered by usecase_port.tmpl, usecase_impl.tmpl,
+// usecase_struct.tmpl and usecase_method.tmpl.
+type Usecase struct {
+	Pkg          string
+	Method       string // PascalCase; empty when only the package is created
+	WithParam    bool
+	WithResponse bool
+	Signature    string // e.g. Submit(ctx context.Context, req SubmitRequest) error
+	ConfigImport string
+	DBImport     string
+}
+
+// Repo is rendered by repo_pkg.tmpl and repo_method.tmpl.
+type Repo struct {
+	Pkg          string
+	PkgPascal    string
+	Method       string
+	WithParam    bool // takes param <Method>Param
+	WithResponse bool // returns <Method>Response
+	WithTx       bool // takes tx pgx.Tx
+	Signature    string
+	SqlcImport   string
+}
+
+// RepoSqlc is rendered by repo_method_sqlc.tmpl: a repo method that calls
+// the sqlc query of the same shape and maps between DTOs and sqlc types.
+type RepoSqlc struct {
+	Repo
+	Query     string   // sqlc Queries method, e.g. GetUserByID
+	Kind      string   // one|many|exec|execrows|execresult
+	ArgType   string   // sqlc params struct, e.g. sqlc.CreateUserParams; "" if scalar/none
+	ArgFields []string // fields copied from param into ArgType
+	ArgField  string   // the scalar argument's Param field, e.g. ID
+	RowFields []string // fields copied from the sqlc row; empty for scalar rows
+	ItemType  string   // element of Response.Items for :many
+}
+
+// SqlcConfig is rendered by sqlc_yaml.tmpl when the project has no sqlc.yaml.
+type SqlcConfig struct {
+	Queries string // first query file
+	Schema  string // migrations dir
+	Out     string // sqlc package dir
+}
+
+// QueryStub is rendered by sql_query_stub.tmpl into <queriesDir>/<pkg>.sql.
+type QueryStub struct {
+	Pkg    string
+	Method string
+	Kind   string // one|many|exec
+}
+
+// Migration is rendered by migration_up.tmpl and migration_down.tmpl
+// (golang-migrate) or migration_goose.tmpl; empty Up/Down render a TODO.
+type Migration struct {
+	Name string // e.g. add_status_to_users
+	Up   string
+	Down string
+}
+
+// Table is rendered by migration_create_table.tmpl.
+type Table struct {
+	Table string // e.g. users
+}
+
+// Outbound is rendered by outbound_port.tmpl, outbound_impl.tmpl and
+// outbound_method.tmpl.
+type Outbound struct {
+	Pkg          string
+	Iface        string // port interface name, PascalCase of Pkg
+	Method       string
+	WithParam    bool
+	WithResponse bool
+	Signature    string
+	ConfigImport string
+}
+
+// DTO is rendered by dto_struct.tmpl for every Request/Response/Param struct.
+type DTO struct {
+	Pkg     string
+	Name    string // e.g. SubmitRequest
+	Comment string // optional doc line without the leading "// "
+}
+
+// Mapper is rendered by mapper_func.tmpl, one conversion function per
+// create-mapper run.
+type Mapper struct {
+	Func   string        // e.g. submitRequestToUserUpdateUserStatusParam
+	From   string        // source type as seen from the usecase pkg, e.g. SubmitRequest
+	To     string        // target type, e.g. user.UpdateUserStatusParam
+	Fields []MapperField // mapped target fields
+	TODOs  []MapperField // target fields left to do by hand, assigned as is so the build flags them
+}
+
+// MapperField is one `Name: Expr,` line of a Mapper.
+type MapperField struct {
+	Name string
+	Expr string // e.g. in.Status, int32(in.Age), pgtype.Text{String: in.Note, Valid: true}
+	Note string // why a TODO is not mapped, e.g. "from Age int64 may not fit; ..."
+}
+
+// Validator is rendered by validate_file.tmpl into a usecase package's
+// validate.go.
+type Validator struct {
+	Pkg     string
+	Header  string   // "Code generated" line; the file is rewritten when it leads
+	Imports []string // standard library only
+	Email   bool     // emit the isEmail helper
+	Types   []ValidatorType
+}
+
+// ValidatorType is one DTO's Validate() method.
+type ValidatorType struct {
+	Name   string
+	Checks []ValidatorCheck
+}
+
+// ValidatorCheck fails with Msg when Cond holds, e.g.
+// {Cond: `r.Email == ""`, Msg: "email is required"}.
+type ValidatorCheck struct {
+	Cond string
+	Msg  string
+}
+
+// UsecaseDI is rendered by usecase_di.tmpl when the aggregated usecase DI
+// file (paths.UsecaseDIPath) does not exist yet.
+type UsecaseDI struct {
+	Pkg          string // first usecase package being registered
+	PkgPascal    string
+	Import       string // import path of that usecase package
+	StructMarker string // paths.UsecaseStructMarker
+}
+
+// RepoDI is rendered by repo_di.tmpl when the repository DI file
+// (paths.PgDiPath) does not exist yet.
+type RepoDI struct {
+	Pkg          string // first repository package being registered
+	PkgPascal    string
+	Import       string // import path of that repository package
+	DBImport     string // import path providing db.TxManager
+	StructMarker string // paths.RepositoryStructMarker
+}
+
+// Bootstrap is rendered by the init_*.tmpl templates used by `ntaps init`.
+type Bootstrap struct {
+	Module                 string
+	Receiver               string // paths.WireReceiver
+	ConfigImport           string
+	DBImport               string // provides TxManager
+	HTTPImport             string // http.Handler interface pkg
+	MiddlewareImport       string
+	UsecaseImport          string // aggregated usecase.UseCase pkg
+	RepositoryImport       string // aggregated db.Repository pkg
+	UsecaseInitMarker      string
+	RepositoryInitMarker   string
+	HandlersSliceMarker    string
+	UsecaseStructMarker    string
+	RepositoryStructMarker string
+}
@@ -0,0 +1,93 @@
+// Package tmpl renders the Go code ntaps generates. Defaults are embedded
+// from templates/*.tmpl; a project can override any of them by putting a
+// file with the same name in paths.TemplatesDir (.ntaps/templates).
+package tmpl
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/AndreeJait/ntaps/internal/paths"
+	"github.com/AndreeJait/ntaps/internal/util"
+)
+
+//go:embed templates/*.tmpl
+var embedded embed.FS
+
+var funcs = template.FuncMap{
+	"pascal":   util.ToPascalCase,
+	"camel":    util.ToCamelCase,
+	"humanize": util.HumanizePascal,
+	"lower":    strings.ToLower,
+	"upper":    strings.ToUpper,
+	"join":     strings.Join,
+}
+
+var set *template.Template
+
+// Names lists the built-in template file names.
+func Names() []string {
+	entries, _ := fs.ReadDir(embedded, "templates")
+	var out []string
+	for _, e := range entries {
+		out = append(out, e.Name())
+	}
+	sort.Strings(out)
+	return out
+}
+
+// Default returns the embedded source of a built-in template.
+func Default(name string) ([]byte, error) {
+	return embedded.ReadFile("templates/" + name)
+}
+
+// load parses every template once per run, preferring project overrides.
+// All templates share one set so they can {{template "x.tmpl" .}} each other.
+func load() (*template.Template, error) {
+	if set != nil {
+		return set, nil
+	}
+	t := template.New("").Funcs(funcs)
+	for _, name := range Names() {
+		src, err := Default(name)
+		if err != nil {
+			return nil, err
+		}
+		override := filepath.Join(paths.TemplatesDir, name)
+		if b, err := os.ReadFile(override); err == nil {
+			src = b
+		}
+		if _, err := t.New(name).Parse(string(src)); err != nil {
+			return nil, fmt.Errorf("parse template %s: %w", name, err)
+		}
+	}
+	set = t
+	return set, nil
+}
+
+// Render executes the named template (e.g. "handler_method.tmpl") with data.
+func Render(name string, data any) (string, error) {
+	t, err := load()
+	if err != nil {
+		return "", err
+	}
+	var b bytes.Buffer
+	if err := t.ExecuteTemplate(&b, name, data); err != nil {
+		return "", fmt.Errorf("render template %s: %w", name, err)
+	}
+	return b.String(), nil
+}
+
+// Check parses every template, including project overrides, and returns
+// the first parse error.
+func Check() error {
+	_, err := load()
+	return err
+}
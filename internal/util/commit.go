@@ -0,0 +1,155 @@
+package util
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// Commit writes every staged change to disk as one unit. All new contents
+// are first written to temp files next to their targets; only when that
+// succeeded are they renamed into place and deletions applied. If any step
+// fails, files already replaced are restored to their original content and
+// files this run created are removed.
+func Commit() error {
+	changes := StagedChanges()
+
+	// 1) directories new files need, plus ones requested via MkdirAll
+	var dirs []string
+	for d := range stagedDirs {
+		dirs = append(dirs, d)
+	}
+	for _, c := range changes {
+		if !c.Deleted {
+			dirs = append(dirs, filepath.Dir(c.Path))
+		}
+	}
+	sort.Strings(dirs)
+	var created []string
+	for _, d := range dirs {
+		made, err := mkdirAllTracked(d)
+		created = append(created, made...)
+		if err != nil {
+			removeDirs(created)
+			return fmt.Errorf("create %s: %w (nothing was written)", d, err)
+		}
+	}
+
+	// 2) temp files
+	temps := map[string]string{}
+	cleanup := func() {
+		for _, t := range temps {
+			_ = os.Remove(t)
+		}
+	}
+	for _, c := range changes {
+		if c.Deleted {
+			continue
+		}
+		tmp, err := writeTemp(c.Path, c.After)
+		if err != nil {
+			cleanup()
+			removeDirs(created)
+			return fmt.Errorf("write %s: %w (nothing was written)", c.Path, err)
+		}
+		temps[c.Path] = tmp
+	}
+
+	// 3) swap into place
+	var done []StagedChange
+	for _, c := range changes {
+		var err error
+		if c.Deleted {
+			err = os.Remove(c.Path)
+			if errors.Is(err, os.ErrNotExist) {
+				err = nil
+			}
+		} else {
+			if err = os.Rename(temps[c.Path], c.Path); err == nil {
+				delete(temps, c.Path)
+			}
+		}
+		if err != nil {
+			cleanup()
+			rollback(done)
+			removeDirs(created)
+			return fmt.Errorf("commit %s: %w (changes rolled back)", c.Path, err)
+		}
+		done = append(done, c)
+	}
+
+	for d := range removedDirs {
+		_ = os.RemoveAll(d)
+	}
+	return nil
+}
+
+// rollback puts the originals of already committed changes back.
+func rollback(done []StagedChange) {
+	for i := len(done) - 1; i >= 0; i-- {
+		c := done[i]
+		if !c.Existed {
+			_ = os.Remove(c.Path)
+			continue
+		}
+		if tmp, err := writeTemp(c.Path, c.Before); err == nil {
+			_ = os.Rename(tmp, c.Path)
+		}
+	}
+}
+
+// writeTemp writes data to a temp file in path's directory, keeping the
+// mode of an existing path.
+func writeTemp(path string, data []byte) (string, error) {
+	mode := os.FileMode(0o644)
+	if fi, err := os.Stat(path); err == nil {
+		mode = fi.Mode().Perm()
+	}
+	f, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".ntaps-*")
+	if err != nil {
+		return "", err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return "", err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return "", err
+	}
+	if err := os.Chmod(f.Name(), mode); err != nil {
+		os.Remove(f.Name())
+		return "", err
+	}
+	return f.Name(), nil
+}
+
+// mkdirAllTracked is os.MkdirAll that reports which directories it made.
+func mkdirAllTracked(dir string) ([]string, error) {
+	var missing []string
+	for d := filepath.Clean(dir); ; d = filepath.Dir(d) {
+		if _, err := os.Stat(d); err == nil {
+			break
+		}
+		missing = append(missing, d)
+		if filepath.Dir(d) == d {
+			break
+		}
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return missing, nil
+}
+
+// removeDirs removes directories this run created, deepest first, as long
+// as they are empty.
+func removeDirs(dirs []string) {
+	sort.Sort(sort.Reverse(sort.StringSlice(dirs)))
+	for _, d := range dirs {
+		_ = os.Remove(d)
+	}
+}
@@ -0,0 +1,215 @@
+package util
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+const diffContext = 3
+
+const (
+	colorReset = "\x1b[0m"
+	colorRed   = "\x1b[31m"
+	colorGreen = "\x1b[32m"
+	colorCyan  = "\x1b[36m"
+	colorBold  = "\x1b[1m"
+)
+
+type diffOp struct {
+	kind byte // ' ', '-', '+'
+	line string
+}
+
+// UnifiedDiff renders a unified diff (3 lines of context) between a and b.
+// It returns "" when both sides are equal.
+func UnifiedDiff(aName, bName string, a, b []byte) string {
+	if string(a) == string(b) {
+		return ""
+	}
+	ops := diffLines(splitLines(string(a)), splitLines(string(b)))
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "--- %s\n+++ %s\n", aName, bName)
+
+	// walk ops, emitting a hunk for each run of changes plus context
+	i := 0
+	for i < len(ops) {
+		if ops[i].kind == ' ' {
+			i++
+			continue
+		}
+		start := i - diffContext
+		if start < 0 {
+			start = 0
+		}
+		end := i
+		for end < len(ops) {
+			if ops[end].kind != ' ' {
+				end++
+				continue
+			}
+			// stop once we've seen more than 2*context unchanged lines
+			run := end
+			for run < len(ops) && ops[run].kind == ' ' {
+				run++
+			}
+			if run == len(ops) || run-end > 2*diffContext {
+				end += diffContext
+				if end > len(ops) {
+					end = len(ops)
+				}
+				break
+			}
+			end = run
+		}
+
+		aStart, bStart := 1, 1
+		for _, op := range ops[:start] {
+			if op.kind != '+' {
+				aStart++
+			}
+			if op.kind != '-' {
+				bStart++
+			}
+		}
+		aLen, bLen := 0, 0
+		for _, op := range ops[start:end] {
+			if op.kind != '+' {
+				aLen++
+			}
+			if op.kind != '-' {
+				bLen++
+			}
+		}
+		if aLen == 0 {
+			aStart--
+		}
+		if bLen == 0 {
+			bStart--
+		}
+
+		fmt.Fprintf(&sb, "@@ -%d,%d +%d,%d @@\n", aStart, aLen, bStart, bLen)
+		for _, op := range ops[start:end] {
+			sb.WriteByte(op.kind)
+			sb.WriteString(op.line)
+			sb.WriteByte('\n')
+		}
+		i = end
+	}
+	return sb.String()
+}
+
+// ColorizeDiff adds ANSI colors to a unified diff.
+func ColorizeDiff(d string) string {
+	lines := strings.SplitAfter(d, "\n")
+	var sb strings.Builder
+	for _, l := range lines {
+		switch {
+		case strings.HasPrefix(l, "---"), strings.HasPrefix(l, "+++"):
+			sb.WriteString(colorBold + strings.TrimSuffix(l, "\n") + colorReset + "\n")
+		case strings.HasPrefix(l, "@@"):
+			sb.WriteString(colorCyan + strings.TrimSuffix(l, "\n") + colorReset + "\n")
+		case strings.HasPrefix(l, "-"):
+			sb.WriteString(colorRed + strings.TrimSuffix(l, "\n") + colorReset + "\n")
+		case strings.HasPrefix(l, "+"):
+			sb.WriteString(colorGreen + strings.TrimSuffix(l, "\n") + colorReset + "\n")
+		default:
+			sb.WriteString(l)
+		}
+	}
+	return sb.String()
+}
+
+// PrintStagedDiff writes a unified diff for every staged change to w.
+// Colors are used only when w is a terminal and NO_COLOR is unset.
+func PrintStagedDiff(w io.Writer) {
+	changes := StagedChanges()
+	if len(changes) == 0 {
+		fmt.Fprintln(w, "🔍 dry-run: no files would change")
+		return
+	}
+
+	color := isTerminal(w) && os.Getenv("NO_COLOR") == ""
+	for _, c := range changes {
+		aName := "a/" + c.Path
+		if !c.Existed {
+			aName = "/dev/null"
+		}
+		bName := "b/" + c.Path
+		if c.Deleted {
+			bName = "/dev/null"
+		}
+		d := UnifiedDiff(aName, bName, c.Before, c.After)
+		if color {
+			d = ColorizeDiff(d)
+		}
+		fmt.Fprint(w, d)
+	}
+	fmt.Fprintf(w, "🔍 dry-run: %d file(s) would change, nothing written\n", len(changes))
+}
+
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return fi.Mode()&os.ModeCharDevice != 0
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
+
+// diffLines computes a line diff from the longest common subsequence.
+// Generated files are small, so the O(n*m) table is fine here.
+func diffLines(a, b []string) []diffOp {
+	n, m := len(a), len(b)
+	lcs := make([][]int, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var ops []diffOp
+	i, j := 0, 0
+	for i < n && j < m {
+		switch {
+		case a[i] == b[j]:
+			ops = append(ops, diffOp{' ', a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			ops = append(ops, diffOp{'-', a[i]})
+			i++
+		default:
+			ops = append(ops, diffOp{'+', b[j]})
+			j++
+		}
+	}
+	for ; i < n; i++ {
+		ops = append(ops, diffOp{'-', a[i]})
+	}
+	for ; j < m; j++ {
+		ops = append(ops, diffOp{'+', b[j]})
+	}
+	return ops
+}
@@ -0,0 +1,114 @@
+package util
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// numbered returns lines "1".."n", one per line.
+func numbered(n int) []string {
+	var out []string
+	for i := 1; i <= n; i++ {
+		out = append(out, fmt.Sprint(i))
+	}
+	return out
+}
+
+func text(lines []string) []byte {
+	if len(lines) == 0 {
+		return nil
+	}
+	return []byte(strings.Join(lines, "\n") + "\n")
+}
+
+// with returns lines with edits applied: key = 1-based line, "" deletes it.
+func with(lines []string, edits map[int]string) []string {
+	var out []string
+	for i, l := range lines {
+		if e, ok := edits[i+1]; ok {
+			if e != "" {
+				out = append(out, e)
+			}
+			continue
+		}
+		out = append(out, l)
+	}
+	return out
+}
+
+func TestUnifiedDiff(t *testing.T) {
+	ten := numbered(10)
+	tests := []struct {
+		name string
+		a, b []string
+		want string // hunks only, without the ---/+++ header
+	}{
+		{
+			name: "equal",
+			a:    ten, b: ten,
+			want: "",
+		},
+		{
+			name: "new file",
+			a:    nil, b: []string{"x", "y"},
+			want: "@@ -0,0 +1,2 @@\n+x\n+y\n",
+		},
+		{
+			name: "deleted file",
+			a:    []string{"x"}, b: nil,
+			want: "@@ -1,1 +0,0 @@\n-x\n",
+		},
+		{
+			name: "change in the middle keeps 3 lines of context",
+			a:    ten, b: with(ten, map[int]string{5: "five"}),
+			want: "@@ -2,7 +2,7 @@\n 2\n 3\n 4\n-5\n+five\n 6\n 7\n 8\n",
+		},
+		{
+			name: "insert at the top",
+			a:    ten, b: append([]string{"0"}, ten...),
+			want: "@@ -1,3 +1,4 @@\n+0\n 1\n 2\n 3\n",
+		},
+		{
+			name: "delete at the end",
+			a:    ten, b: ten[:9],
+			want: "@@ -7,4 +7,3 @@\n 7\n 8\n 9\n-10\n",
+		},
+		{
+			name: "changes 6 lines apart share a hunk",
+			a:    numbered(20), b: with(numbered(20), map[int]string{3: "", 10: "ten"}),
+			want: "@@ -1,13 +1,12 @@\n 1\n 2\n-3\n 4\n 5\n 6\n 7\n 8\n 9\n-10\n+ten\n 11\n 12\n 13\n",
+		},
+		{
+			name: "changes 7 lines apart get two hunks",
+			a:    numbered(20), b: with(numbered(20), map[int]string{2: "two", 10: "ten"}),
+			want: "@@ -1,5 +1,5 @@\n 1\n-2\n+two\n 3\n 4\n 5\n" +
+				"@@ -7,7 +7,7 @@\n 7\n 8\n 9\n-10\n+ten\n 11\n 12\n 13\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := UnifiedDiff("a/f.go", "b/f.go", text(tt.a), text(tt.b))
+			want := tt.want
+			if want != "" {
+				want = "--- a/f.go\n+++ b/f.go\n" + want
+			}
+			if got != want {
+				t.Errorf("diff:\n%s\nwant:\n%s", got, want)
+			}
+		})
+	}
+}
+
+func TestColorizeDiff(t *testing.T) {
+	d := "--- a/f\n+++ b/f\n@@ -1,2 +1,2 @@\n ctx\n-old\n+new\n"
+	want := colorBold + "--- a/f" + colorReset + "\n" +
+		colorBold + "+++ b/f" + colorReset + "\n" +
+		colorCyan + "@@ -1,2 +1,2 @@" + colorReset + "\n" +
+		" ctx\n" +
+		colorRed + "-old" + colorReset + "\n" +
+		colorGreen + "+new" + colorReset + "\n"
+	if got := ColorizeDiff(d); got != want {
+		t.Errorf("ColorizeDiff = %q, want %q", got, want)
+	}
+}
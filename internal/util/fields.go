@@ -0,0 +1,149 @@
+package util
+
+import (
+	"fmt"
+	"go/parser"
+	"regexp"
+	"strings"
+)
+
+// Field is one "name:type[:rules]" entry of --field / --fields.
+type Field struct {
+	Name  string // as written, used for json/db/query tags (e.g. "created_at")
+	Type  string // Go type (e.g. "time.Time")
+	Rules string // validate rules (e.g. "required,email"); optional
+}
+
+// GoName is the exported Go field name, e.g. created_at -> CreatedAt.
+func (f Field) GoName() string { return ExportedName(f.Name) }
+
+// Tagged renders f as a struct field tagged `<key>:"<name>"`, plus
+// `validate:"<rules>"` when f has rules.
+func (f Field) Tagged(key string) StructField {
+	tag := fmt.Sprintf(`%s:"%s"`, key, f.Name)
+	if f.Rules != "" {
+		tag += fmt.Sprintf(` validate:"%s"`, f.Rules)
+	}
+	return StructField{Name: f.GoName(), Type: f.Type, Tag: tag}
+}
+
+// TagFields renders fields with Tagged(key).
+func TagFields(fields []Field, key string) []StructField {
+	out := make([]StructField, 0, len(fields))
+	for _, f := range fields {
+		out = append(out, f.Tagged(key))
+	}
+	return out
+}
+
+// typeAliases lets --fields use short names for common non-builtin types.
+var typeAliases = map[string]string{
+	"time":      "time.Time",
+	"timestamp": "time.Time",
+	"date":      "time.Time",
+	"decimal":   "float64",
+	"text":      "string",
+}
+
+var fieldNameRe = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// paramNameRe also admits HTTP header names such as X-Request-ID.
+var paramNameRe = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.-]*$`)
+
+// knownImports are the packages field types commonly use, imported
+// explicitly so goimports does not have to guess (pgtype exists for
+// several pgx majors).
+var knownImports = map[string]string{
+	"time":    "time",
+	"json":    "encoding/json",
+	"sql":     "database/sql",
+	"netip":   "net/netip",
+	"pgtype":  "github.com/jackc/pgx/v5/pgtype",
+	"uuid":    "github.com/google/uuid",
+	"decimal": "github.com/shopspring/decimal",
+}
+
+var qualifierRe = regexp.MustCompile(`\b([a-z]\w*)\.`)
+
+// FieldImports lists the known imports the fields' types refer to.
+func FieldImports(fields []StructField) []string {
+	seen := map[string]bool{}
+	var out []string
+	for _, f := range fields {
+		for _, m := range qualifierRe.FindAllStringSubmatch(f.Type, -1) {
+			if imp, ok := knownImports[m[1]]; ok && !seen[imp] {
+				seen[imp] = true
+				out = append(out, imp)
+			}
+		}
+	}
+	return out
+}
+
+// ParseField parses one "email:string:required,email".
+func ParseField(spec string) (Field, error) {
+	return parseField(spec, fieldNameRe)
+}
+
+func parseField(spec string, nameRe *regexp.Regexp) (Field, error) {
+	parts := strings.SplitN(strings.TrimSpace(spec), ":", 3)
+	if len(parts) < 2 || strings.TrimSpace(parts[0]) == "" || strings.TrimSpace(parts[1]) == "" {
+		return Field{}, fmt.Errorf("field %q must be name:type[:rules]", spec)
+	}
+	f := Field{Name: strings.TrimSpace(parts[0]), Type: strings.TrimSpace(parts[1])}
+	if len(parts) == 3 {
+		f.Rules = strings.TrimSpace(parts[2])
+	}
+	if !nameRe.MatchString(f.Name) {
+		return Field{}, fmt.Errorf("field name %q is not an identifier", f.Name)
+	}
+	if a, ok := typeAliases[f.Type]; ok {
+		f.Type = a
+	}
+	if _, err := parser.ParseExpr(f.Type); err != nil {
+		return Field{}, fmt.Errorf("field %s: %q is not a Go type", f.Name, f.Type)
+	}
+	return f, nil
+}
+
+// ParseFields parses "name:string,email:string:required,email,status:int".
+// A comma-separated piece without a colon continues the previous field's
+// rules.
+func ParseFields(spec string) ([]Field, error) {
+	return parseFields(spec, fieldNameRe)
+}
+
+// ParseParams is ParseFields for query/header/cookie parameters, whose
+// names may contain dashes and dots ("X-Request-ID:string,page:int").
+func ParseParams(spec string) ([]Field, error) {
+	return parseFields(spec, paramNameRe)
+}
+
+func parseFields(spec string, nameRe *regexp.Regexp) ([]Field, error) {
+	var specs []string
+	for _, part := range strings.Split(spec, ",") {
+		if strings.TrimSpace(part) == "" {
+			continue
+		}
+		if !strings.Contains(part, ":") && len(specs) > 0 {
+			specs[len(specs)-1] += "," + strings.TrimSpace(part)
+			continue
+		}
+		specs = append(specs, part)
+	}
+
+	var out []Field
+	seen := map[string]bool{}
+	for _, s := range specs {
+		f, err := parseField(s, nameRe)
+		if err != nil {
+			return nil, err
+		}
+		if seen[f.Name] {
+			return nil, fmt.Errorf("field %s declared twice", f.Name)
+		}
+		seen[f.Name] = true
+		out = append(out, f)
+	}
+	return out, nil
+}
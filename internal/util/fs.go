@@ -0,0 +1,206 @@
+package util
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Every generator reads and writes through ReadFile / Stat / MkdirAll /
+// WriteGoFile. Writes are always staged in memory: a dry run prints them
+// as a diff, a normal run writes them all at the end with Commit, so a
+// command that fails halfway leaves the tree untouched.
+
+type stagedFile struct {
+	orig    []byte
+	existed bool
+	data    []byte
+	deleted bool
+}
+
+var (
+	dryRun      bool
+	staged      = map[string]*stagedFile{}
+	stagedDirs  = map[string]bool{}
+	removedDirs = map[string]bool{}
+)
+
+// SetDryRun makes the run print its staged changes instead of committing.
+func SetDryRun(v bool) { dryRun = v }
+
+// IsDryRun reports whether the staged changes will only be shown.
+func IsDryRun() bool { return dryRun }
+
+// ReadFile returns the staged content of path if this run already wrote it,
+// otherwise the content on disk.
+func ReadFile(path string) ([]byte, error) {
+	if f, ok := staged[filepath.Clean(path)]; ok {
+		if f.deleted {
+			return nil, &fs.PathError{Op: "open", Path: path, Err: fs.ErrNotExist}
+		}
+		return append([]byte(nil), f.data...), nil
+	}
+	if isRemoved(filepath.Clean(path)) {
+		return nil, &fs.PathError{Op: "open", Path: path, Err: fs.ErrNotExist}
+	}
+	return os.ReadFile(path)
+}
+
+// Stat is os.Stat that also sees files and directories staged by this run.
+func Stat(path string) (os.FileInfo, error) {
+	p := filepath.Clean(path)
+	if f, ok := staged[p]; ok && !f.deleted {
+		return stagedInfo{name: filepath.Base(p), size: int64(len(f.data))}, nil
+	} else if ok {
+		return nil, &fs.PathError{Op: "stat", Path: path, Err: fs.ErrNotExist}
+	}
+	if fi, err := os.Stat(p); err == nil && !isRemoved(p) {
+		return fi, nil
+	}
+	if stagedDirs[p] {
+		return stagedInfo{name: filepath.Base(p), dir: true}, nil
+	}
+	prefix := p + string(filepath.Separator)
+	for k, f := range staged {
+		if strings.HasPrefix(k, prefix) && !f.deleted {
+			return stagedInfo{name: filepath.Base(p), dir: true}, nil
+		}
+	}
+	return nil, &fs.PathError{Op: "stat", Path: path, Err: fs.ErrNotExist}
+}
+
+// Glob is filepath.Glob over the disk as this run sees it: staged files are
+// included and staged deletions left out. The result is sorted.
+func Glob(pattern string) ([]string, error) {
+	matches, err := filepath.Glob(pattern)
+	if err != nil {
+		return nil, err
+	}
+	seen := map[string]bool{}
+	var out []string
+	for _, m := range matches {
+		if _, err := Stat(m); err == nil {
+			seen[filepath.Clean(m)] = true
+			out = append(out, m)
+		}
+	}
+	for p, f := range staged {
+		if ok, _ := filepath.Match(pattern, p); ok && !f.deleted && !seen[p] {
+			out = append(out, p)
+		}
+	}
+	sort.Strings(out)
+	return out, nil
+}
+
+// MkdirAll stages the creation of dir.
+func MkdirAll(dir string) error {
+	stagedDirs[filepath.Clean(dir)] = true
+	return nil
+}
+
+// RemoveFile stages the deletion of path.
+func RemoveFile(path string) error {
+	f := stage(filepath.Clean(path))
+	f.data, f.deleted = nil, true
+	return nil
+}
+
+// RemoveAll stages the deletion of dir and every file in it, so each
+// removed file shows up in the diff.
+func RemoveAll(dir string) error {
+	p := filepath.Clean(dir)
+	_ = filepath.WalkDir(p, func(path string, d fs.DirEntry, err error) error {
+		if err == nil && !d.IsDir() {
+			return RemoveFile(path)
+		}
+		return nil
+	})
+	prefix := p + string(filepath.Separator)
+	for k, f := range staged {
+		if strings.HasPrefix(k, prefix) {
+			f.data, f.deleted = nil, true
+		}
+	}
+	delete(stagedDirs, p)
+	removedDirs[p] = true
+	return nil
+}
+
+// isRemoved reports whether p lies in a directory removed by this run.
+func isRemoved(p string) bool {
+	for d := range removedDirs {
+		if p == d || strings.HasPrefix(p, d+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
+
+// writeFile stages data as the new content of path.
+func writeFile(path string, data []byte) error {
+	f := stage(filepath.Clean(path))
+	f.data, f.deleted = append([]byte(nil), data...), false
+	return nil
+}
+
+// stage returns the staged entry for p, snapshotting the on-disk original
+// the first time p is touched.
+func stage(p string) *stagedFile {
+	f, ok := staged[p]
+	if !ok {
+		f = &stagedFile{}
+		if orig, err := os.ReadFile(p); err == nil {
+			f.orig, f.existed = orig, true
+		}
+		staged[p] = f
+	}
+	return f
+}
+
+// StagedChange is one file created or modified by the current run.
+type StagedChange struct {
+	Path    string
+	Existed bool
+	Deleted bool
+	Before  []byte
+	After   []byte
+}
+
+// StagedChanges lists the staged files whose content differs from disk,
+// sorted by path.
+func StagedChanges() []StagedChange {
+	var out []StagedChange
+	for p, f := range staged {
+		if f.deleted && !f.existed {
+			continue
+		}
+		if f.existed && !f.deleted && string(f.orig) == string(f.data) {
+			continue
+		}
+		out = append(out, StagedChange{Path: p, Existed: f.existed, Deleted: f.deleted, Before: f.orig, After: f.data})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Path < out[j].Path })
+	return out
+}
+
+type stagedInfo struct {
+	name string
+	size int64
+	dir  bool
+}
+
+func (s stagedInfo) Name() string { return s.name }
+func (s stagedInfo) Size() int64  { return s.size }
+func (s stagedInfo) Mode() fs.FileMode {
+	if s.dir {
+		return fs.ModeDir | 0o755
+	}
+	return 0o644
+}
+func (s stagedInfo) ModTime() time.Time { return time.Time{} }
+func (s stagedInfo) IsDir() bool        { return s.dir }
+func (s stagedInfo) Sys() any           { return nil }
@@ -28,3 +28,35 @@ func InsertImport(src, importLine string) string {
 	}
 	return src[:idx+1] + "import (\n\t" + importLine + "\n)\n" + src[idx+1:]
 }
+
+// HasField reports whether src declares a struct field `name typ`,
+// regardless of the column alignment gofmt applied to it.
+func HasField(src, name, typ string) bool {
+	re := regexp.MustCompile(`(?m)^\s*` + regexp.QuoteMeta(name) + `\s+` + regexp.QuoteMeta(typ) + `\s*(//.*)?$`)
+	return re.MatchString(src)
+}
+
+// MarkerRegexp compiles a literal marker such as "func (s wire) initUseCase("
+// into a regexp that tolerates any whitespace where the marker has some.
+func MarkerRegexp(marker string) *regexp.Regexp {
+	fields := strings.Fields(marker)
+	for i, f := range fields {
+		fields[i] = regexp.QuoteMeta(f)
+	}
+	re := strings.Join(fields, `\s+`)
+	// "func (s wire)" should also match "func (s  wire )"
+	re = strings.ReplaceAll(re, `\(`, `\(\s*`)
+	re = strings.ReplaceAll(re, `\)`, `\s*\)`)
+	return regexp.MustCompile(re)
+}
+
+// FuncBodyStart returns the index just past the "{" that opens the body of
+// the function whose header starts before from (typically the end of a
+// MarkerRegexp match), or -1.
+func FuncBodyStart(src string, from int) int {
+	i := strings.Index(src[from:], "{")
+	if i == -1 {
+		return -1
+	}
+	return from + i + 1
+}
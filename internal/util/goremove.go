@@ -0,0 +1,167 @@
+package util
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"regexp"
+	"strings"
+)
+
+// The Remove* helpers cut a declaration out of Go source by its AST
+// position, together with its doc comment, so the removal commands can
+// reverse exactly what the generators appended. They return src unchanged
+// and false when the declaration is not there.
+
+// RemoveFunc removes `func (<recv>) name(...)`; recv is the receiver type
+// without "*", or "" for a plain function.
+func RemoveFunc(src, recv, name string) (string, bool) {
+	fset, f, ok := parseSrc(src)
+	if !ok {
+		return src, false
+	}
+	for _, d := range f.Decls {
+		fd, ok := d.(*ast.FuncDecl)
+		if !ok || fd.Name.Name != name || recvName(fd) != recv {
+			continue
+		}
+		return cutNode(src, fset, fd.Doc, fd), true
+	}
+	return src, false
+}
+
+// RemoveType removes `type name ...`. Grouped type declarations lose only
+// that spec.
+func RemoveType(src, name string) (string, bool) {
+	fset, f, ok := parseSrc(src)
+	if !ok {
+		return src, false
+	}
+	for _, d := range f.Decls {
+		gd, ok := d.(*ast.GenDecl)
+		if !ok || gd.Tok != token.TYPE {
+			continue
+		}
+		for _, s := range gd.Specs {
+			ts := s.(*ast.TypeSpec)
+			if ts.Name.Name != name {
+				continue
+			}
+			if len(gd.Specs) == 1 {
+				return cutNode(src, fset, gd.Doc, gd), true
+			}
+			return cutNode(src, fset, ts.Doc, ts), true
+		}
+	}
+	return src, false
+}
+
+// RemoveInterfaceMethod removes method from `type iface interface {...}`.
+func RemoveInterfaceMethod(src, iface, method string) (string, bool) {
+	return removeMember(src, iface, method, func(t ast.Expr) *ast.FieldList {
+		if it, ok := t.(*ast.InterfaceType); ok {
+			return it.Methods
+		}
+		return nil
+	})
+}
+
+// RemoveStructField removes field from `type name struct {...}`. A field
+// declared together with others (`a, b int`) is left alone.
+func RemoveStructField(src, name, field string) (string, bool) {
+	return removeMember(src, name, field, func(t ast.Expr) *ast.FieldList {
+		if st, ok := t.(*ast.StructType); ok {
+			return st.Fields
+		}
+		return nil
+	})
+}
+
+// RemoveLines drops every line matching re and reports how many went.
+func RemoveLines(src string, re *regexp.Regexp) (string, int) {
+	lines := strings.SplitAfter(src, "\n")
+	var b strings.Builder
+	n := 0
+	for _, l := range lines {
+		if re.MatchString(l) {
+			n++
+			continue
+		}
+		b.WriteString(l)
+	}
+	return b.String(), n
+}
+
+func removeMember(src, typeName, member string, list func(ast.Expr) *ast.FieldList) (string, bool) {
+	fset, f, ok := parseSrc(src)
+	if !ok {
+		return src, false
+	}
+	var out string
+	found := false
+	ast.Inspect(f, func(n ast.Node) bool {
+		ts, ok := n.(*ast.TypeSpec)
+		if found || !ok || ts.Name.Name != typeName {
+			return !found
+		}
+		fl := list(ts.Type)
+		if fl == nil {
+			return false
+		}
+		for _, fld := range fl.List {
+			if len(fld.Names) == 1 && fld.Names[0].Name == member {
+				out, found = cutNode(src, fset, fld.Doc, fld), true
+				return false
+			}
+		}
+		return false
+	})
+	if !found {
+		return src, false
+	}
+	return out, true
+}
+
+// cutNode removes the whole lines spanned by doc (if any) and node.
+func cutNode(src string, fset *token.FileSet, doc *ast.CommentGroup, node ast.Node) string {
+	start := fset.Position(node.Pos()).Offset
+	if doc != nil {
+		start = fset.Position(doc.Pos()).Offset
+	}
+	end := fset.Position(node.End()).Offset
+
+	if i := strings.LastIndex(src[:start], "\n"); i != -1 {
+		start = i + 1
+	} else {
+		start = 0
+	}
+	if i := strings.Index(src[end:], "\n"); i != -1 {
+		end += i + 1
+	} else {
+		end = len(src)
+	}
+	return src[:start] + src[end:]
+}
+
+func recvName(fd *ast.FuncDecl) string {
+	if fd.Recv == nil || len(fd.Recv.List) == 0 {
+		return ""
+	}
+	t := fd.Recv.List[0].Type
+	if star, ok := t.(*ast.StarExpr); ok {
+		t = star.X
+	}
+	if id, ok := t.(*ast.Ident); ok {
+		return id.Name
+	}
+	return ""
+}
+
+func parseSrc(src string) (*token.FileSet, *ast.File, bool) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "", src, parser.ParseComments)
+	if err != nil {
+		return nil, nil, false
+	}
+	return fset, f, true
+}
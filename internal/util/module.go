@@ -1,13 +1,13 @@
 package util
 
 import (
-	"os"
+	"path/filepath"
 	"strings"
 )
 
 // ModulePathGuess tries to read first line of go.mod.
 func ModulePathGuess() string {
-	b, err := os.ReadFile("go.mod")
+	b, err := ReadFile("go.mod")
 	if err != nil {
 		return "go-template-hexagonal"
 	}
@@ -17,3 +17,10 @@ func ModulePathGuess() string {
 	}
 	return "go-template-hexagonal"
 }
+
+// ImportPath turns a project-relative directory into a Go import path
+// using the module from go.mod, e.g. internal/usecase/send →
+// github.com/acme/svc/internal/usecase/send.
+func ImportPath(dir ...string) string {
+	return ModulePathGuess() + "/" + filepath.ToSlash(filepath.Join(dir...))
+}
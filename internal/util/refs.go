@@ -0,0 +1,88 @@
+package util
+
+import (
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Reference is one line of Go source that still uses a symbol.
+type Reference struct {
+	File string
+	Line int
+	Text string
+}
+
+func (r Reference) String() string {
+	return fmt.Sprintf("%s:%d: %s", r.File, r.Line, r.Text)
+}
+
+// FindReferences scans every .go file under the current directory for re,
+// skipping the given files and directories (the ones being removed or
+// rewritten). Staged content is used for files this run already touched.
+func FindReferences(re *regexp.Regexp, skip ...string) []Reference {
+	skipped := map[string]bool{}
+	for _, s := range skip {
+		skipped[filepath.Clean(s)] = true
+	}
+
+	var out []Reference
+	_ = filepath.WalkDir(".", func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if skipped[filepath.Clean(path)] {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() {
+			switch d.Name() {
+			case ".git", ".ntaps", "vendor", "node_modules":
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(path, ".go") {
+			return nil
+		}
+		raw, err := ReadFile(path)
+		if err != nil {
+			return nil
+		}
+		out = append(out, MatchLines(path, string(raw), re)...)
+		return nil
+	})
+	return out
+}
+
+// MatchLines returns the lines of src matching re as references in file.
+func MatchLines(file, src string, re *regexp.Regexp) []Reference {
+	var out []Reference
+	for i, l := range strings.Split(src, "\n") {
+		if re.MatchString(l) {
+			out = append(out, Reference{File: file, Line: i + 1, Text: strings.TrimSpace(l)})
+		}
+	}
+	return out
+}
+
+// GuardReferences refuses to remove what while refs still point at it,
+// unless force is set, in which case the call sites are only printed.
+func GuardReferences(what string, refs []Reference, force bool) error {
+	if len(refs) == 0 {
+		return nil
+	}
+	var b strings.Builder
+	for _, r := range refs {
+		b.WriteString("\n    " + r.String())
+	}
+	if !force {
+		return fmt.Errorf("%s is still referenced (remove these first or pass --force):%s", what, b.String())
+	}
+	fmt.Printf("ℹ️  warning: %s is still referenced:%s\n", what, b.String())
+	return nil
+}
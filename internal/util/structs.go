@@ -0,0 +1,169 @@
+package util
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// StructField is one field EnsureStructFields adds, e.g.
+// {Name: "Email", Type: "string", Tag: `json:"email"`}.
+type StructField struct {
+	Name string
+	Type string
+	Tag  string // without backticks; optional
+}
+
+var (
+	todoFieldsRe = regexp.MustCompile(`(?m)^[ \t]*// TODO: define fields[ \t]*\n`)
+	tagPairRe    = regexp.MustCompile(`\w+:"[^"]*"`)
+)
+
+// EnsureStructFields appends the fields src's `type <typeName> struct` is
+// missing (matched by name or by the first key:"value" of the tag, so
+// `param:"id"` and `param:"id" validate:"required"` are the same field) and
+// drops the "// TODO: define fields" placeholder once the struct has real fields.
+func EnsureStructFields(src, typeName string, fields []StructField) (string, error) {
+	fset, st, err := findStruct(src, typeName)
+	if err != nil {
+		return src, err
+	}
+
+	have := map[string]bool{}
+	tags := map[string]bool{}
+	for _, fld := range st.Fields.List {
+		for _, n := range fld.Names {
+			have[n.Name] = true
+		}
+		if fld.Tag != nil {
+			for _, pair := range tagPairRe.FindAllString(fld.Tag.Value, -1) {
+				tags[pair] = true
+			}
+		}
+		if len(fld.Names) == 0 { // embedded
+			have[strings.TrimPrefix(exprString(src, fset, fld.Type), "*")] = true
+		}
+	}
+
+	var add strings.Builder
+	for _, fld := range fields {
+		if have[fld.Name] || tags[tagPairRe.FindString(fld.Tag)] {
+			continue
+		}
+		have[fld.Name] = true
+		add.WriteString("\t" + fld.Name + " " + fld.Type)
+		if fld.Tag != "" {
+			add.WriteString(" `" + fld.Tag + "`")
+		}
+		add.WriteString("\n")
+	}
+
+	open := fset.Position(st.Fields.Opening).Offset
+	closing := fset.Position(st.Fields.Closing).Offset
+	body := src[open+1 : closing]
+	if add.Len() == 0 && (len(st.Fields.List) == 0 || !todoFieldsRe.MatchString(body)) {
+		return src, nil
+	}
+
+	if !strings.HasSuffix(body, "\n") {
+		body += "\n"
+	}
+	body += add.String()
+	body = todoFieldsRe.ReplaceAllString(body, "")
+	return src[:open+1] + body + src[closing:], nil
+}
+
+// StructTags lists the named fields of typeName with their tags (without
+// backticks).
+func StructTags(src, typeName string) []StructField {
+	fset, st, err := findStruct(src, typeName)
+	if err != nil {
+		return nil
+	}
+	var out []StructField
+	for _, fld := range st.Fields.List {
+		tag := ""
+		if fld.Tag != nil {
+			tag, _ = strconv.Unquote(fld.Tag.Value)
+		}
+		for _, n := range fld.Names {
+			out = append(out, StructField{Name: n.Name, Type: exprString(src, fset, fld.Type), Tag: tag})
+		}
+	}
+	return out
+}
+
+// SetFieldTag sets key:"value" in the tag of typeName's field called name
+// (its Go name or its json/query/param/form/header/cookie name), keeping the other keys.
+func SetFieldTag(src, typeName, name, key, value string) (string, error) {
+	fset, st, err := findStruct(src, typeName)
+	if err != nil {
+		return src, err
+	}
+	for _, fld := range st.Fields.List {
+		tag := ""
+		if fld.Tag != nil {
+			tag, _ = strconv.Unquote(fld.Tag.Value)
+		}
+		if !fieldIs(fld, reflect.StructTag(tag), name) {
+			continue
+		}
+		pair := fmt.Sprintf(`%s:"%s"`, key, value)
+		if _, ok := reflect.StructTag(tag).Lookup(key); ok {
+			tag = regexp.MustCompile(`\b`+regexp.QuoteMeta(key)+`:"[^"]*"`).ReplaceAllLiteralString(tag, pair)
+		} else {
+			tag = strings.TrimSpace(tag + " " + pair)
+		}
+		if fld.Tag != nil {
+			start, end := fset.Position(fld.Tag.Pos()).Offset, fset.Position(fld.Tag.End()).Offset
+			return src[:start] + "`" + tag + "`" + src[end:], nil
+		}
+		end := fset.Position(fld.Type.End()).Offset
+		return src[:end] + " `" + tag + "`" + src[end:], nil
+	}
+	return src, fmt.Errorf("struct %s has no field %s", typeName, name)
+}
+
+func fieldIs(fld *ast.Field, tag reflect.StructTag, name string) bool {
+	for _, n := range fld.Names {
+		if n.Name == name {
+			return true
+		}
+	}
+	for _, key := range []string{"json", "query", "param", "form", "header", "cookie"} {
+		if v, _, _ := strings.Cut(tag.Get(key), ","); v == name {
+			return len(fld.Names) > 0
+		}
+	}
+	return false
+}
+
+// findStruct parses src and returns `type <typeName> struct`.
+func findStruct(src, typeName string) (*token.FileSet, *ast.StructType, error) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "", src, parser.ParseComments)
+	if err != nil {
+		return nil, nil, err
+	}
+	var st *ast.StructType
+	ast.Inspect(f, func(n ast.Node) bool {
+		if ts, ok := n.(*ast.TypeSpec); ok && ts.Name.Name == typeName {
+			st, _ = ts.Type.(*ast.StructType)
+			return false
+		}
+		return st == nil
+	})
+	if st == nil {
+		return nil, nil, fmt.Errorf("struct %s not found", typeName)
+	}
+	return fset, st, nil
+}
+
+func exprString(src string, fset *token.FileSet, e ast.Expr) string {
+	return src[fset.Position(e.Pos()).Offset:fset.Position(e.End()).Offset]
+}
@@ -33,6 +33,36 @@ func ToCamelCase(s string) string {
 	return strings.ToLower(p[:1]) + p[1:]
 }
 
+// ExportedName is like ToPascalCase but keeps existing humps, for names that
+// come from outside (OpenAPI, SQL): getUser -> GetUser, user_id -> UserID,
+// orderId -> OrderID.
+func ExportedName(s string) string {
+	parts := wordRe.FindAllString(s, -1)
+	for i, p := range parts {
+		if strings.EqualFold(p, "id") {
+			parts[i] = "ID"
+			continue
+		}
+		if n := len(p); n > 2 && strings.HasSuffix(p, "Id") && p[n-3] >= 'a' && p[n-3] <= 'z' {
+			p = p[:n-2] + "ID"
+		}
+		parts[i] = strings.ToUpper(p[:1]) + p[1:]
+	}
+	return strings.Join(parts, "")
+}
+
+// Plural is deliberately naive: Category -> Categories, Address -> Addresses.
+func Plural(s string) string {
+	switch {
+	case len(s) > 1 && strings.HasSuffix(s, "y") && !strings.ContainsAny(s[len(s)-2:len(s)-1], "aeiou"):
+		return s[:len(s)-1] + "ies"
+	case strings.HasSuffix(s, "s"), strings.HasSuffix(s, "x"), strings.HasSuffix(s, "ch"), strings.HasSuffix(s, "sh"):
+		return s + "es"
+	default:
+		return s + "s"
+	}
+}
+
 func HumanizePascal(s string) string {
 	re := regexp.MustCompile(`([a-z0-9])([A-Z])`)
 	return strings.TrimSpace(re.ReplaceAllString(s, "$1 $2"))
@@ -2,8 +2,6 @@ package util
 
 import (
 	"go/format"
-	"os"
-	"path/filepath"
 
 	"golang.org/x/tools/imports"
 )
@@ -11,10 +9,6 @@ import (
 // WriteGoFile formats + fixes imports, then writes.
 // Falls back to raw write on parse failure so we never block scaffolding.
 func WriteGoFile(path string, content string) error {
-	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
-		return err
-	}
-
 	formatted, err := imports.Process(path, []byte(content), &imports.Options{
 		Comments:   true,
 		FormatOnly: false,
@@ -29,5 +23,10 @@ func WriteGoFile(path string, content string) error {
 		}
 	}
 
-	return os.WriteFile(path, formatted, 0o644)
+	return writeFile(path, formatted)
+}
+
+// WriteFile writes a non-Go file (SQL, YAML, templates...) as-is.
+func WriteFile(path string, content string) error {
+	return writeFile(path, []byte(content))
 }